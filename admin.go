@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"sync"
+)
+
+type adminServer struct {
+	mu     sync.Mutex
+	server *http.Server
+}
+
+// AdminHandler serves the operational endpoints of the app: /healthz,
+// /readyz, /config, /deps, /loglevel and /debug/pprof/.
+func (a *app) AdminHandler() http.Handler {
+
+	mux := http.NewServeMux()
+
+	mux.Handle("/healthz", a.health.Handler(LivenessProbe))
+	mux.Handle("/readyz", a.health.Handler(ReadinessProbe))
+
+	mux.HandleFunc("/config", func(w http.ResponseWriter, r *http.Request) {
+		writeAdminJson(w, a.opts.EffectiveConfig())
+	})
+
+	mux.HandleFunc("/deps", func(w http.ResponseWriter, r *http.Request) {
+		writeAdminJson(w, a.lifecycle.tree.info())
+	})
+
+	mux.Handle("/loglevel", a.LogLevelHandler())
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	return mux
+
+}
+
+func writeAdminJson(w http.ResponseWriter, val any) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(val)
+
+}
+
+// startAdmin reports whether the server was started by this call.
+func (a *app) startAdmin() (bool, error) {
+
+	if !a.AdminEnabled.IsSet() || !a.AdminEnabled.BoolVal() {
+		return false, nil
+	}
+
+	a.admin.mu.Lock()
+	defer a.admin.mu.Unlock()
+
+	if a.admin.server != nil {
+		return false, nil
+	}
+
+	listener, err := net.Listen("tcp", a.AdminAddr.StringVal())
+	if err != nil {
+		return false, err
+	}
+
+	server := &http.Server{Handler: a.AdminHandler()}
+
+	a.admin.server = server
+
+	a.Log().Info("Admin server listening", "addr", listener.Addr().String())
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			a.Log().Error("Admin server failed", "error", err)
+		}
+	}()
+
+	return true, nil
+
+}
+
+func (a *app) stopAdmin(ctx context.Context) error {
+
+	a.admin.mu.Lock()
+	server := a.admin.server
+	a.admin.server = nil
+	a.admin.mu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+
+	return server.Shutdown(ctx)
+
+}
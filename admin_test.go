@@ -0,0 +1,83 @@
+package app_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	app "github.com/protomesh/go-app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type adminRoot struct {
+	*app.Injector[*adminRoot]
+
+	User     app.Config `config:"store.user,str"`
+	Password app.Config `config:"store.password,str"`
+	Dsn      app.Config `config:"store.dsn,str" secret:"true"`
+
+	Store *adminStore
+}
+
+type adminStore struct {
+	*app.Injector[*adminRoot]
+}
+
+func TestAppAdminHandler(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-store-user", "admin", "-store-password", "hunter2", "-store-dsn", "pg://admin:hunter2@db"}
+
+	a := app.NewApp(&adminRoot{}, opts)
+	defer a.Close()
+
+	handler := a.AdminHandler()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	config := map[string]any{}
+	assert.NoError(t, json.Unmarshal(get("/config").Body.Bytes(), &config))
+	assert.Equal(t, "admin", config["store.user"])
+	assert.Equal(t, "[REDACTED]", config["store.password"])
+	assert.Equal(t, "[REDACTED]", config["store.dsn"])
+	assert.Equal(t, "30s", config["shutdown.timeout"])
+
+	deps := get("/deps").Body.String()
+	assert.Contains(t, deps, `"path": "adminRoot.Store"`)
+	assert.Contains(t, deps, `"type": "*app_test.adminStore"`)
+
+	assert.Equal(t, http.StatusServiceUnavailable, get("/readyz").Code)
+	assert.Equal(t, http.StatusOK, get("/healthz").Code)
+
+	assert.JSONEq(t, `{"level":"debug"}`, get("/loglevel").Body.String())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/loglevel", strings.NewReader(`{"level":"error"}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"error"}`, get("/loglevel").Body.String())
+
+	assert.Equal(t, http.StatusOK, get("/debug/pprof/").Code)
+
+}
+
+func TestAppAdminServerLifecycle(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-admin-enabled", "-admin-addr", "127.0.0.1:0"}
+
+	a := app.NewApp(&adminRoot{}, opts)
+	defer a.Close()
+
+	assert.NoError(t, a.Start(context.Background()))
+	assert.ErrorIs(t, a.Start(context.Background()), app.AppAlreadyStartedError)
+	assert.NoError(t, a.Stop(context.Background()))
+
+}
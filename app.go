@@ -1,9 +1,13 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"reflect"
+	"sync"
 
 	"github.com/jedib0t/go-pretty/v6/list"
 )
@@ -23,6 +27,18 @@ type Dependency interface {
 type Injector[D any] struct {
 	app App
 	dep D
+
+	logApp  App
+	logName string
+	logOnce sync.Once
+	log     Logger
+}
+
+// namedDependency is implemented by Injector to receive the logger name of
+// its dependency in the tree.
+type namedDependency interface {
+	setLogger(app App, name string)
+	attachRoot(app App, dep any)
 }
 
 func (a *Injector[D]) Attach(app any, dep any) {
@@ -30,12 +46,64 @@ func (a *Injector[D]) Attach(app any, dep any) {
 	a.dep = dep.(D)
 }
 
+func (a *Injector[D]) setLogger(app App, name string) {
+	a.logApp = app
+	a.logName = name
+}
+
+// attachRoot attaches the injector of the root dependency to the app, when
+// the root satisfies its dependency type.
+func (a *Injector[D]) attachRoot(app App, dep any) {
+
+	if d, ok := dep.(D); ok && a.app == nil {
+		a.app = app
+		a.dep = d
+	}
+
+}
+
 func (a *Injector[D]) Dependency() D {
 	return a.dep
 }
 
+// Log returns the app logger named after the path of the dependency in the
+// tree, or the logger tag of its field.
 func (a *Injector[Dependency]) Log() Logger {
-	return a.app.Log()
+
+	if a.logApp == nil {
+		return a.app.Log()
+	}
+
+	a.logOnce.Do(func() {
+		a.log = NameLogger(a.logApp.Log(), a.logName)
+	})
+
+	return a.log
+
+}
+
+// Supervisor returns the supervisor of the app created by NewApp, or nil for
+// other App implementations.
+func (a *Injector[Dependency]) Supervisor() *Supervisor {
+
+	if sa, ok := a.app.(interface{ Supervisor() *Supervisor }); ok {
+		return sa.Supervisor()
+	}
+
+	return nil
+
+}
+
+// Health returns the health registry of the app created by NewApp, or nil for
+// other App implementations.
+func (a *Injector[Dependency]) Health() *HealthRegistry {
+
+	if ha, ok := a.app.(interface{ Health() *HealthRegistry }); ok {
+		return ha.Health()
+	}
+
+	return nil
+
 }
 
 func Inject[D any](app App, dep D) {
@@ -47,15 +115,66 @@ func InjectAndPrint[D any](app App, dep D) {
 }
 
 func InjectAny[D any](app any, dep D, print bool, lw list.Writer) {
+	injectDependencies(app, dep, print, lw)
+}
 
-	depVal := reflect.ValueOf(dep)
-	appDep := reflect.TypeOf((*Dependency)(nil)).Elem()
+func injectDependencies(app any, dep any, print bool, lw list.Writer) *dependencyNode {
 
 	if print {
 		lw = list.NewWriter()
 		lw.SetStyle(list.StyleBulletSquare)
 	}
 
+	root := newDependencyNode(nil, dependencyTypeName(reflect.TypeOf(dep)), "", dep)
+
+	logApp, _ := app.(App)
+
+	if logApp != nil {
+		attachRootInjector(logApp, root)
+	}
+
+	injectNode(app, logApp, root, lw)
+
+	if print {
+		fmt.Println("Dependency hierarchy:")
+		fmt.Println(lw.Render())
+	}
+
+	return root
+
+}
+
+func attachRootInjector(app App, root *dependencyNode) {
+
+	depVal := reflect.ValueOf(root.value)
+
+	if depVal.Kind() != reflect.Ptr || depVal.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	appInj := depVal.Elem().FieldByName("Injector")
+	if appInj.Kind() != reflect.Ptr || !appInj.CanSet() {
+		return
+	}
+
+	if appInj.IsZero() {
+		appInj.Set(reflect.New(appInj.Type().Elem()))
+	}
+
+	if named, ok := appInj.Interface().(namedDependency); ok {
+		named.attachRoot(app, root.value)
+		named.setLogger(app, root.logName)
+	}
+
+}
+
+func injectNode(app any, logApp App, node *dependencyNode, lw list.Writer) {
+
+	dep := node.value
+
+	depVal := reflect.ValueOf(dep)
+	appDep := reflect.TypeOf((*Dependency)(nil)).Elem()
+
 	if depVal.Kind() == reflect.Ptr && depVal.Elem().Kind() == reflect.Struct {
 
 		depEl := depVal.Elem()
@@ -82,8 +201,10 @@ func InjectAny[D any](app any, dep D, print bool, lw list.Writer) {
 					appInj.Set(reflect.New(appInj.Type().Elem()))
 				}
 
+				field := depType.Elem().Field(i)
+
 				if lw != nil {
-					lw.AppendItem(fmt.Sprintf("%s\n[%s ---> %s]\n", depType.Elem().Field(i).Name, depType.String(), fieldVal.Type().String()))
+					lw.AppendItem(fmt.Sprintf("%s\n[%s ---> %s]\n", field.Name, depType.String(), fieldVal.Type().String()))
 					lw.Indent()
 				}
 
@@ -95,7 +216,13 @@ func InjectAny[D any](app any, dep D, print bool, lw list.Writer) {
 
 				fieldInst := fieldVal.Interface()
 
-				InjectAny(fieldInst, fieldInst, false, lw)
+				child := newDependencyNode(node, field.Name, field.Tag.Get("logger"), fieldInst)
+
+				if named, ok := appInst.(namedDependency); ok && logApp != nil {
+					named.setLogger(logApp, child.logName)
+				}
+
+				injectNode(fieldInst, logApp, child, lw)
 
 				if lw != nil {
 					lw.UnIndent()
@@ -107,25 +234,80 @@ func InjectAny[D any](app any, dep D, print bool, lw list.Writer) {
 
 	}
 
-	if print {
-		fmt.Println("Dependency hierarchy:")
-		fmt.Println(lw.Render())
-	}
-
 }
 
 type AppWithClose interface {
 	App
 	Close()
+
+	// Start starts the dependencies implementing Starter, depth-first, then
+	// the supervised workers.
+	Start(ctx context.Context) error
+
+	// Stop stops the supervised workers, then the started dependencies
+	// implementing Stopper in reverse order, within shutdown.timeout when the
+	// context has no deadline.
+	Stop(ctx context.Context) error
+
+	Supervisor() *Supervisor
+
+	// Health returns the registry of the liveness and readiness checks,
+	// including the dependencies implementing HealthChecker or
+	// LivenessChecker.
+	Health() *HealthRegistry
+
+	// SetLogLevel changes the level of the app logger when the name is empty,
+	// otherwise of the loggers with that name or ending with it, and of their
+	// nested names: "vet" applies to "root.PetStore.vet" and its nested
+	// loggers. The longest match wins. It returns LogLevelsUnsupportedError
+	// with AppOptions.Logger or LoggerFactory.
+	SetLogLevel(name string, level LogLevel) error
+
+	// ResetLogLevel removes the level of the named loggers.
+	ResetLogLevel(name string) error
+
+	// LogLevels returns the level of the app logger and of the named loggers,
+	// or an empty level with a custom logger.
+	LogLevels() (LogLevel, map[string]LogLevel)
+
+	LogLevelHandler() http.Handler
+
+	// AdminHandler serves the endpoints of the admin server, which is
+	// started with the app when admin.enabled is set.
+	AdminHandler() http.Handler
+
+	// Reload loads the configuration sources again, applies the values of the
+	// fields tagged with reloadable:"true" and reopens the log outputs.
+	Reload() error
+
+	// Run starts the dependencies, runs fn until a shutdown signal and stops
+	// the dependencies.
+	Run(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type app struct {
+	lifecycle  lifecycle
+	supervisor *Supervisor
+	health     *HealthRegistry
+	admin      adminServer
+	logLevels  *logLevels
+
+	reloadMu  sync.Mutex
+	opts      *AppOptions
+	keySets   []any
+	logOutput interface{ reopen() error }
+
 	log interface {
 		Logger
 		Sync() error
 	}
 
-	ConfigFile Config `config:"config.file,str" usage:"Path to config file (JSON, TOML or YAML)"`
+	ConfigFile      Config `config:"config.file,str" usage:"Path to config file (JSON, TOML or YAML)"`
+	ShutdownTimeout Config `config:"shutdown.timeout,duration" default:"30s" reloadable:"true" usage:"Grace period to stop the application"`
+	HealthTimeout   Config `config:"health.timeout,duration" default:"5s" usage:"Timeout of each health check"`
+	HealthCacheTTL  Config `config:"health.cache.ttl,duration" default:"1s" usage:"Time to reuse the result of a health check, zero disables caching"`
+	AdminEnabled    Config `config:"admin.enabled,bool" default:"false" usage:"Serve the admin endpoints (health, config, dependencies, log level and pprof)"`
+	AdminAddr       Config `config:"admin.addr,str" default:"127.0.0.1:9090" usage:"Address of the admin server, only reachable locally by default"`
 }
 
 func NewApp[D Dependency](deps D, opts *AppOptions) AppWithClose {
@@ -133,6 +315,10 @@ func NewApp[D Dependency](deps D, opts *AppOptions) AppWithClose {
 	logBuilder := &loggerBuilder[D]{}
 	appInstance := &app{}
 
+	defaults := opts.ApplyDefaults(appInstance, logBuilder, deps)
+
+	sources := []ConfigSource{}
+
 	if opts.FlagSet != nil {
 
 		args := opts.Args
@@ -144,14 +330,23 @@ func NewApp[D Dependency](deps D, opts *AppOptions) AppWithClose {
 		opts.ApplyFlags(deps)
 		opts.ApplyFlags(logBuilder)
 
+		if opts.ProtoConfig != nil {
+			opts.ApplyFlags(opts.ProtoConfig)
+		}
+
 		opts.FlagSet.Parse(args)
 
+		sources = append(sources, NewFlagSource(JsonPathCase, opts.FlagSet))
+
 	}
 
-	cfg := NewCompositeSource(
-		NewFlagSource(JsonPathCase, opts.FlagSet),
-		NewEnvSource(JsonPathCase),
-	)
+	if opts.Sources != nil {
+		sources = append(sources, opts.Sources...)
+	} else {
+		sources = append(sources, NewEnvSource(JsonPathCase))
+	}
+
+	cfg := NewCompositeSource(append(sources[:len(sources):len(sources)], defaults)...)
 
 	err := cfg.Load()
 	if err != nil {
@@ -164,11 +359,9 @@ func NewApp[D Dependency](deps D, opts *AppOptions) AppWithClose {
 
 	if appInstance.ConfigFile.IsSet() {
 
-		cfg = NewCompositeSource(
-			NewFlagSource(JsonPathCase, opts.FlagSet),
-			NewEnvSource(JsonPathCase),
-			NewFileSource(appInstance.ConfigFile.StringVal()),
-		)
+		sources = append(sources, NewFileSource(appInstance.ConfigFile.StringVal()))
+
+		cfg = NewCompositeSource(append(sources[:len(sources):len(sources)], defaults)...)
 
 		err := cfg.Load()
 		if err != nil {
@@ -181,22 +374,85 @@ func NewApp[D Dependency](deps D, opts *AppOptions) AppWithClose {
 
 	opts.ApplyConfigs(logBuilder)
 
-	appInstance.log = logBuilder.build()
+	switch {
+
+	case opts.Logger != nil:
+
+		appInstance.setCustomLogger(opts.Logger)
+
+	case opts.LoggerFactory != nil:
+
+		logConfig, err := logBuilder.loggerConfig()
+		if err != nil {
+			panic(err)
+		}
+
+		logger, err := opts.LoggerFactory(logConfig)
+		if err != nil {
+			panic(err)
+		}
+
+		appInstance.setCustomLogger(logger)
+
+	default:
+
+		appInstance.log = logBuilder.build()
+		appInstance.logOutput = logBuilder
+		appInstance.logLevels = logBuilder.levels
+
+	}
+
+	opts.getState().setLogger(appInstance.log)
+
+	appInstance.supervisor = NewSupervisor(appInstance.log)
+
+	healthCacheTTL := appInstance.HealthCacheTTL.DurationVal()
+
+	// Zero would fall back to the default of the registry
+	if healthCacheTTL <= 0 {
+		healthCacheTTL = -1
+	}
+
+	appInstance.health = NewHealthRegistry(&HealthCheckOptions{
+		Timeout:  appInstance.HealthTimeout.DurationVal(),
+		CacheTTL: healthCacheTTL,
+	})
+
+	appInstance.health.Register("app", ReadinessProbe, appInstance.lifecycle.checkReady, &HealthCheckOptions{CacheTTL: -1})
 
 	opts.Source = cfg
 
-	if opts.Print {
-		InjectAndPrint(appInstance, deps)
-	} else {
-		Inject(appInstance, deps)
+	appInstance.lifecycle.tree = injectDependencies(appInstance, deps, opts.Print, nil)
+
+	if err := appInstance.health.registerDependencyChecks(appInstance.lifecycle.tree); err != nil {
+		panic(err)
 	}
 
 	opts.ApplyConfigs(deps)
 
+	if opts.ProtoConfig != nil {
+		opts.ApplyConfigs(opts.ProtoConfig)
+	}
+
+	appInstance.opts = opts
+	appInstance.keySets = []any{appInstance, logBuilder, deps}
+
 	return appInstance
 
 }
 
+func (a *app) setCustomLogger(logger Logger) {
+
+	custom := &customLogger{logger}
+
+	a.log = custom
+	a.logOutput = custom
+
+	// The levels of a custom logger are its own
+	a.logLevels = nil
+
+}
+
 func (a *app) Log() Logger {
 	return a.log
 }
@@ -204,3 +460,67 @@ func (a *app) Log() Logger {
 func (a *app) Close() {
 	a.log.Sync()
 }
+
+func (a *app) Start(ctx context.Context) error {
+
+	adminStarted, err := a.startAdmin()
+	if err != nil {
+		return err
+	}
+
+	if err := a.lifecycle.start(ctx); err != nil {
+
+		if adminStarted {
+			err = errors.Join(err, a.stopAdmin(context.Background()))
+		}
+
+		return err
+
+	}
+
+	return a.supervisor.Start(ctx)
+
+}
+
+func (a *app) Stop(ctx context.Context) error {
+
+	ctx, cancel := stopContext(ctx, a.shutdownTimeout())
+	defer cancel()
+
+	return errors.Join(a.supervisor.Stop(ctx), a.lifecycle.stop(ctx, 0), a.stopAdmin(ctx))
+
+}
+
+func (a *app) Supervisor() *Supervisor {
+	return a.supervisor
+}
+
+func (a *app) Health() *HealthRegistry {
+	return a.health
+}
+
+func (a *app) Reload() error {
+
+	a.reloadMu.Lock()
+	defer a.reloadMu.Unlock()
+
+	err := a.opts.Source.Load()
+	if err == nil {
+		err = a.opts.ReloadConfigs(a.keySets...)
+	}
+
+	if reopenErr := a.logOutput.reopen(); reopenErr != nil {
+		a.Log().Error("Failed to reopen log outputs", "error", reopenErr)
+		err = errors.Join(err, reopenErr)
+	}
+
+	if err != nil {
+		a.Log().Error("Failed to reload configuration, keeping the previous one", "error", err)
+		return err
+	}
+
+	a.Log().Info("Configuration reloaded")
+
+	return nil
+
+}
@@ -0,0 +1,227 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	app "github.com/protomesh/go-app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lifecycleEvents struct {
+	events []string
+	failOn string
+}
+
+type lifecycleStep struct {
+	name string
+	log  *lifecycleEvents
+}
+
+func (s *lifecycleStep) Start(ctx context.Context) error {
+
+	if s.log.failOn == s.name {
+		return errors.New("boom")
+	}
+
+	s.log.events = append(s.log.events, "start "+s.name)
+
+	return nil
+
+}
+
+func (s *lifecycleStep) Stop(ctx context.Context) error {
+
+	s.log.events = append(s.log.events, "stop "+s.name)
+
+	return nil
+
+}
+
+type lifecycleLeaf struct {
+	*app.Injector[*lifecycleBranch]
+	lifecycleStep
+}
+
+type lifecycleBranch struct {
+	*app.Injector[*lifecycleRoot]
+	lifecycleStep
+
+	Leaf *lifecycleLeaf
+}
+
+type lifecycleRoot struct {
+	*app.Injector[*lifecycleRoot]
+	lifecycleStep
+
+	Branch *lifecycleBranch
+	Other  *lifecycleLeafOfRoot
+}
+
+type lifecycleLeafOfRoot struct {
+	*app.Injector[*lifecycleRoot]
+	lifecycleStep
+}
+
+func newLifecycleRoot(log *lifecycleEvents) *lifecycleRoot {
+
+	return &lifecycleRoot{
+		lifecycleStep: lifecycleStep{name: "root", log: log},
+		Branch: &lifecycleBranch{
+			lifecycleStep: lifecycleStep{name: "branch", log: log},
+			Leaf:          &lifecycleLeaf{lifecycleStep: lifecycleStep{name: "leaf", log: log}},
+		},
+		Other: &lifecycleLeafOfRoot{lifecycleStep: lifecycleStep{name: "other", log: log}},
+	}
+
+}
+
+func TestAppStartStopOrder(t *testing.T) {
+
+	log := &lifecycleEvents{}
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{}
+
+	a := app.NewApp(newLifecycleRoot(log), opts)
+	defer a.Close()
+
+	assert.NoError(t, a.Start(context.Background()))
+	assert.ErrorIs(t, a.Start(context.Background()), app.AppAlreadyStartedError)
+	assert.NoError(t, a.Stop(context.Background()))
+
+	assert.Equal(t, []string{
+		"start leaf", "start branch", "start other", "start root",
+		"stop root", "stop other", "stop branch", "stop leaf",
+	}, log.events)
+
+}
+
+func TestAppStartFailureStopsStarted(t *testing.T) {
+
+	log := &lifecycleEvents{failOn: "other"}
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{}
+
+	a := app.NewApp(newLifecycleRoot(log), opts)
+	defer a.Close()
+
+	err := a.Start(context.Background())
+	assert.ErrorContains(t, err, "failed to start lifecycleRoot.Other: boom")
+
+	assert.Equal(t, []string{
+		"start leaf", "start branch",
+		"stop branch", "stop leaf",
+	}, log.events)
+
+}
+
+func TestAppRunReturnsFatalError(t *testing.T) {
+
+	log := &lifecycleEvents{}
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{}
+
+	a := app.NewApp(newLifecycleRoot(log), opts)
+	defer a.Close()
+
+	fatal := errors.New("fatal")
+
+	err := a.Run(context.Background(), func(ctx context.Context) error {
+		return fatal
+	})
+
+	assert.ErrorIs(t, err, fatal)
+	assert.Equal(t, "stop leaf", log.events[len(log.events)-1])
+
+}
+
+func TestAppRunStopsOnContextCancel(t *testing.T) {
+
+	log := &lifecycleEvents{}
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{}
+
+	a := app.NewApp(newLifecycleRoot(log), opts)
+	defer a.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := a.Run(ctx, func(ctx context.Context) error {
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, log.events, 8)
+
+}
+
+func TestAppRunShutdownTimeout(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-shutdown-timeout", "10ms"}
+
+	a := app.NewApp(newLifecycleRoot(&lifecycleEvents{}), opts)
+	defer a.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	block := make(chan struct{})
+	defer close(block)
+
+	err := a.Run(ctx, func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+
+	assert.ErrorIs(t, err, app.ShutdownTimeoutError)
+
+}
+
+type deadlineRecorder struct {
+	*app.Injector[*deadlineRecorder]
+
+	stopErr error
+}
+
+func (d *deadlineRecorder) Stop(ctx context.Context) error {
+
+	d.stopErr = ctx.Err()
+
+	return nil
+
+}
+
+func TestAppRunStopsAfterShutdownTimeout(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-shutdown-timeout", "10ms"}
+
+	root := &deadlineRecorder{}
+
+	a := app.NewApp(root, opts)
+	defer a.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	block := make(chan struct{})
+	defer close(block)
+
+	err := a.Run(ctx, func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+
+	assert.ErrorIs(t, err, app.ShutdownTimeoutError)
+	assert.NoError(t, root.stopErr)
+
+}
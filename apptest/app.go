@@ -0,0 +1,62 @@
+package apptest
+
+import (
+	"context"
+	"flag"
+	"io"
+	"testing"
+
+	app "github.com/protomesh/go-app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// App is an App logging to a test Logger.
+type App struct {
+	Logger *Logger
+}
+
+func (a *App) Log() app.Logger {
+	return a.Logger
+}
+
+// Inject injects the dependencies with an App logging to a new Logger, the
+// dependency loggers are named after their path in the tree like with NewApp.
+func Inject[D any](deps D) *Logger {
+
+	log := NewLogger()
+
+	app.Inject[D](&App{Logger: log}, deps)
+
+	return log
+
+}
+
+// New builds an app with NewApp reading the configuration only from the
+// values, keys like "db.dsn" or nested maps, and the defaults, logging to a
+// new Logger. Neither the command line nor the environment is read, and the
+// app is stopped and closed when the test finishes.
+func New[D app.Dependency](t testing.TB, deps D, config map[string]interface{}) (app.AppWithClose, *Logger) {
+
+	t.Helper()
+
+	log := NewLogger()
+
+	fs := flag.NewFlagSet(t.Name(), flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	a := app.NewApp(deps, &app.AppOptions{
+		FlagSet: fs,
+		Args:    []string{},
+		Sources: []app.ConfigSource{app.NewMapSource(config)},
+		Logger:  log,
+	})
+
+	t.Cleanup(func() {
+		assert.NoError(t, a.Stop(context.Background()))
+		a.Close()
+	})
+
+	return a, log
+
+}
@@ -0,0 +1,73 @@
+package apptest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	app "github.com/protomesh/go-app"
+	"github.com/protomesh/go-app/apptest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type harnessRoot struct {
+	*app.Injector[*harnessRoot]
+
+	Dsn     app.Config `config:"db.dsn,str" usage:"Database DSN"`
+	Timeout app.Config `config:"db.timeout,duration" default:"5s" usage:"Database timeout"`
+	Pool    app.Config `config:"db.pool.size,int" default:"4" usage:"Pool size"`
+
+	Replicas []*harnessReplica `config:"db.replicas"`
+
+	Worker *harnessWorker
+}
+
+type harnessReplica struct {
+	Addr app.Config `config:"addr,str" usage:"Replica address"`
+}
+
+type harnessWorker struct {
+	*app.Injector[*harnessRoot]
+
+	started bool
+}
+
+func (w *harnessWorker) Start(ctx context.Context) error {
+
+	w.started = true
+	w.Log().Info("Worker started", "dsn", w.Dependency().Dsn.StringVal())
+
+	return nil
+
+}
+
+func TestNewInjectsConfig(t *testing.T) {
+
+	t.Setenv("DB_POOL_SIZE", "16")
+
+	deps := &harnessRoot{}
+
+	a, log := apptest.New(t, deps, map[string]interface{}{
+		"db.dsn": "postgres://localhost/pets",
+		"db": map[string]interface{}{
+			"timeout":  time.Second,
+			"replicas": []interface{}{map[string]interface{}{"addr": "replica-0"}},
+		},
+	})
+
+	assert.Equal(t, "postgres://localhost/pets", deps.Dsn.StringVal())
+	assert.Equal(t, time.Second, deps.Timeout.DurationVal())
+	assert.Equal(t, int64(4), deps.Pool.Int64Val())
+
+	if assert.Len(t, deps.Replicas, 1) {
+		assert.Equal(t, "replica-0", deps.Replicas[0].Addr.StringVal())
+	}
+
+	assert.NoError(t, a.Start(context.Background()))
+	assert.True(t, deps.Worker.started)
+
+	log.AssertLogged(t, app.LogLevelInfo, "Worker started", "dsn", "postgres://localhost/pets")
+	assert.Equal(t, "harnessRoot.Worker", log.Find(app.LogLevelInfo, "Worker started")[0].Logger)
+
+}
@@ -0,0 +1,171 @@
+// Package apptest helps testing the dependencies of an app, without building
+// it with NewApp.
+package apptest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	app "github.com/protomesh/go-app"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// Entry is a message recorded by a Logger.
+type Entry struct {
+	Level   app.LogLevel
+	Logger  string
+	Message string
+	Fields  map[string]interface{}
+}
+
+func (e Entry) String() string {
+	return fmt.Sprintf("%s [%s] %s %v", e.Level, e.Logger, e.Message, e.Fields)
+}
+
+// Logger records the messages logged in memory, including the ones of the
+// loggers derived with With and Named. Fatal panics instead of exiting.
+type Logger struct {
+	app.Logger
+
+	logs *observer.ObservedLogs
+}
+
+func NewLogger() *Logger {
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	return &Logger{
+		Logger: app.NewZapLogger(zap.New(core, zap.WithFatalHook(zapcore.WriteThenPanic))),
+		logs:   logs,
+	}
+
+}
+
+// Named returns a logger named after name, recorded with the other messages.
+func (l *Logger) Named(name string) app.Logger {
+	return app.NameLogger(l.Logger, name)
+}
+
+// Entries returns the messages recorded so far.
+func (l *Logger) Entries() []Entry {
+
+	logged := l.logs.All()
+
+	entries := make([]Entry, len(logged))
+
+	for i, e := range logged {
+		entries[i] = Entry{
+			Level:   app.LogLevel(e.Level.String()),
+			Logger:  e.LoggerName,
+			Message: e.Message,
+			Fields:  e.ContextMap(),
+		}
+	}
+
+	return entries
+
+}
+
+// Reset drops the messages recorded so far.
+func (l *Logger) Reset() {
+	l.logs.TakeAll()
+}
+
+// Find returns the messages of the level containing the substring and having
+// the key-values.
+func (l *Logger) Find(level app.LogLevel, msgSubstring string, kv ...interface{}) []Entry {
+
+	found := []Entry{}
+
+	for _, entry := range l.Entries() {
+		if entry.Level == level && strings.Contains(entry.Message, msgSubstring) && hasFields(entry, kv) {
+			found = append(found, entry)
+		}
+	}
+
+	return found
+
+}
+
+// AssertLogged checks a message of the level containing the substring and
+// having the key-values was logged.
+func (l *Logger) AssertLogged(t testing.TB, level app.LogLevel, msgSubstring string, kv ...interface{}) bool {
+
+	t.Helper()
+
+	if len(l.Find(level, msgSubstring, kv...)) > 0 {
+		return true
+	}
+
+	return assert.Fail(t, fmt.Sprintf("No %s message containing %q with %v", level, msgSubstring, kv), "Logged:\n%s", l.dump())
+
+}
+
+// AssertNotLogged checks no message of the level containing the substring and
+// having the key-values was logged.
+func (l *Logger) AssertNotLogged(t testing.TB, level app.LogLevel, msgSubstring string, kv ...interface{}) bool {
+
+	t.Helper()
+
+	found := l.Find(level, msgSubstring, kv...)
+	if len(found) == 0 {
+		return true
+	}
+
+	return assert.Fail(t, fmt.Sprintf("Unexpected %s message containing %q with %v", level, msgSubstring, kv), "Logged:\n%s", dumpEntries(found))
+
+}
+
+func (l *Logger) dump() string {
+	return dumpEntries(l.Entries())
+}
+
+func dumpEntries(entries []Entry) string {
+
+	lines := make([]string, len(entries))
+
+	for i, entry := range entries {
+		lines[i] = entry.String()
+	}
+
+	return strings.Join(lines, "\n")
+
+}
+
+// hasFields compares the values the way zap encodes them, so an int matches
+// an int64 and an error matches its message.
+func hasFields(entry Entry, kv []interface{}) bool {
+
+	for i := 0; i < len(kv); i += 2 {
+
+		key := fmt.Sprint(kv[i])
+
+		val, ok := entry.Fields[key]
+		if !ok {
+			return false
+		}
+
+		if i+1 == len(kv) {
+			continue
+		}
+
+		expected := kv[i+1]
+
+		if err, ok := expected.(error); ok {
+			expected = err.Error()
+		}
+
+		if !assert.ObjectsAreEqualValues(expected, val) {
+			return false
+		}
+
+	}
+
+	return true
+
+}
@@ -0,0 +1,64 @@
+package apptest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	app "github.com/protomesh/go-app"
+	"github.com/protomesh/go-app/apptest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRoot struct {
+	*app.Injector[*testRoot]
+
+	Store *testStore `logger:"store"`
+}
+
+type testStore struct {
+	*app.Injector[*testRoot]
+}
+
+func TestLoggerRecordsEntries(t *testing.T) {
+
+	log := apptest.NewLogger()
+
+	log.Named("db").With("table", "pets").Warn("Slow query", "duration", 250, "error", errors.New("timeout"))
+	log.InfoCtx(app.WithLogFields(context.Background(), "request", "r1"), "Handled")
+
+	log.AssertLogged(t, app.LogLevelWarn, "Slow", "table", "pets", "duration", 250, "error", errors.New("timeout"))
+	log.AssertLogged(t, app.LogLevelInfo, "Handled", "request", "r1")
+	log.AssertNotLogged(t, app.LogLevelError, "")
+
+	entries := log.Entries()
+
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "db", entries[0].Logger)
+
+	assert.Empty(t, log.Find(app.LogLevelWarn, "Slow", "table", "owners"))
+
+	log.Reset()
+
+	assert.Empty(t, log.Entries())
+
+	assert.Panics(t, func() { log.Fatal("Unrecoverable") })
+	log.AssertLogged(t, app.LogLevelFatal, "Unrecoverable")
+
+}
+
+func TestInjectNamesLoggers(t *testing.T) {
+
+	deps := &testRoot{}
+
+	log := apptest.Inject(deps)
+
+	deps.Log().Info("From root")
+	deps.Store.Log().Debug("From store")
+
+	assert.Equal(t, []string{"testRoot", "testRoot.store"}, []string{log.Entries()[0].Logger, log.Entries()[1].Logger})
+
+	log.AssertLogged(t, app.LogLevelDebug, "From store")
+
+}
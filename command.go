@@ -1,16 +1,156 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"os"
 	"os/signal"
+	"syscall"
+	"time"
 )
 
+var ShutdownTimeoutError = errors.New("ShutdownTimeout")
+
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP}
+
+// WaitInterruption blocks until the process receives SIGINT or SIGTERM.
 func WaitInterruption() {
 
 	sigCh := make(chan os.Signal, 1)
 
-	signal.Notify(sigCh, os.Interrupt)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
 
 	<-sigCh
 
 }
+
+// Run starts the dependencies and calls fn with a context that is canceled on
+// SIGINT or SIGTERM, when ctx is done or when fn returns. Then fn is given
+// shutdown.timeout to finish, and the dependencies another shutdown.timeout to
+// stop, a second signal exits the process right away. The first fatal error
+// is returned. SIGHUP reloads the configuration while running.
+func (a *app) Run(ctx context.Context, fn func(ctx context.Context) error) error {
+
+	sigCh := make(chan os.Signal, 2)
+
+	signal.Notify(sigCh, shutdownSignals...)
+	defer signal.Stop(sigCh)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if err := a.Start(runCtx); err != nil {
+		return err
+	}
+
+	fnErrCh := make(chan error, 1)
+
+	go func() {
+		fnErrCh <- fn(runCtx)
+	}()
+
+	var fnErr error
+	fnDone := false
+
+	for running := true; running; {
+
+		select {
+
+		case fnErr = <-fnErrCh:
+			fnDone = true
+			running = false
+
+		case sig := <-sigCh:
+
+			if sig == syscall.SIGHUP {
+				a.Reload()
+				continue
+			}
+
+			a.Log().Info("Shutting down", "signal", sig.String())
+			running = false
+
+		case <-ctx.Done():
+			a.Log().Info("Shutting down", "reason", ctx.Err().Error())
+			running = false
+
+		}
+
+	}
+
+	cancel()
+
+	timeout := a.shutdownTimeout()
+
+	// Signals force the exit until the dependencies are stopped
+	stopped := make(chan struct{})
+	defer close(stopped)
+
+	go func() {
+
+		for {
+
+			select {
+
+			case sig := <-sigCh:
+
+				if sig == syscall.SIGHUP {
+					continue
+				}
+
+				a.Log().Error("Forcing exit", "signal", sig.String())
+				a.log.Sync()
+				os.Exit(1)
+
+			case <-stopped:
+				return
+
+			}
+
+		}
+
+	}()
+
+	if !fnDone {
+
+		fnTimer := time.NewTimer(timeout)
+
+		select {
+
+		case fnErr = <-fnErrCh:
+
+		case <-fnTimer.C:
+			fnErr = ShutdownTimeoutError
+
+		}
+
+		fnTimer.Stop()
+
+	}
+
+	// The dependencies get their own deadline, fn may have used up its own
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), timeout)
+	defer stopCancel()
+
+	stopErr := a.Stop(stopCtx)
+
+	if fnErr != nil && !errors.Is(fnErr, context.Canceled) {
+		return fnErr
+	}
+
+	return stopErr
+
+}
+
+func (a *app) shutdownTimeout() time.Duration {
+
+	if a.ShutdownTimeout.IsSet() {
+		if timeout := a.ShutdownTimeout.DurationVal(); timeout > 0 {
+			return timeout
+		}
+	}
+
+	return defaultStopTimeout
+
+}
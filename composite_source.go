@@ -1,19 +1,53 @@
 package app
 
+import "sync"
+
 type compositeSource struct {
-	s   []ConfigSource
+	s []ConfigSource
+
+	mu  sync.Mutex
 	crs map[string]Config
 }
 
 func NewCompositeSource(s ...ConfigSource) ConfigSource {
-	return &compositeSource{
+
+	c := &compositeSource{
 		s:   s,
 		crs: make(map[string]Config),
 	}
+
+	// A changed key can be nested under the cached ones or the other way
+	// around, so drop them all
+	c.OnChange(func(string) {
+		c.mu.Lock()
+		c.crs = make(map[string]Config)
+		c.mu.Unlock()
+	})
+
+	return c
+
+}
+
+func (c *compositeSource) OnChange(fn func(key string)) {
+
+	for _, s := range c.s {
+
+		if notifier, ok := s.(ConfigChangeNotifier); ok {
+			notifier.OnChange(fn)
+		}
+
+	}
+
 }
 
 func (c *compositeSource) Load() error {
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Drop the values resolved from the previous load
+	c.crs = make(map[string]Config)
+
 	for _, cs := range c.s {
 
 		err := cs.Load()
@@ -29,6 +63,9 @@ func (c *compositeSource) Load() error {
 
 func (c *compositeSource) Get(k string) Config {
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if cr, ok := c.crs[k]; ok {
 		return cr
 	}
@@ -59,10 +96,51 @@ func (c *compositeSource) Get(k string) Config {
 	return EmptyConfig()
 }
 
+func (c *compositeSource) Keys(prefix string) []string {
+
+	children := newChildKeySet()
+
+	for _, s := range c.s {
+
+		for _, key := range listConfigKeys(s, prefix) {
+			children.add(key)
+		}
+
+	}
+
+	return children.sorted()
+
+}
+
 func (c *compositeSource) Has(k string) bool {
+	return c.hasExcept(k, nil)
+}
+
+// hasExcept reports whether a source other than skip has the key.
+func (c *compositeSource) hasExcept(k string, skip ConfigSource) bool {
+
+	for _, s := range c.s {
 
-	cfg := c.Get(k)
+		if s == skip {
+			continue
+		}
+
+		if nested, ok := s.(*compositeSource); ok {
+
+			if nested.hasExcept(k, skip) {
+				return true
+			}
+
+			continue
+
+		}
+
+		if s.Has(k) {
+			return true
+		}
+
+	}
 
-	return cfg != nil && cfg.IsSet()
+	return false
 
 }
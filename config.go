@@ -10,10 +10,14 @@ import (
 	"time"
 
 	"github.com/jedib0t/go-pretty/v6/table"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 var (
-	UnkownConfigFormatError = errors.New("UnkownConfigFormat")
+	UnkownConfigFormatError    = errors.New("UnkownConfigFormat")
+	MissingRequiredConfigError = errors.New("MissingRequiredConfig")
+	ConfigIndexGapError        = errors.New("ConfigIndexGap")
 )
 
 type Config interface {
@@ -35,14 +39,50 @@ type ConfigSource interface {
 	Has(k string) bool
 }
 
+// ConfigKeyLister is implemented by sources able to enumerate the keys
+// nested under a prefix, returning only the next key segment of each.
+type ConfigKeyLister interface {
+	Keys(prefix string) []string
+}
+
+// ConfigChangeNotifier is implemented by sources whose values change between
+// loads, like MapSource, calling the functions with the changed keys. The app
+// only drops the values it cached, the Config fields are applied again on
+// Reload, so call it from OnChange to apply the changes.
+type ConfigChangeNotifier interface {
+	OnChange(fn func(key string))
+}
+
 type AppOptions struct {
-	tw table.Writer
+	tw      table.Writer
+	state   *configState
+	visited map[configVisit]bool
 
 	Print   bool
 	Source  ConfigSource
 	FlagSet *flag.FlagSet
 	Prefix  string
 	Args    []string
+
+	// ProtoConfig is an optional protobuf message populated by NewApp from the
+	// merged sources, with flags and keys derived from its descriptor.
+	ProtoConfig proto.Message
+
+	// Defaults is an optional struct pointer, tagged like the dependency tree,
+	// whose set Config fields take precedence over the `default` tags.
+	Defaults any
+
+	// Sources are consulted by NewApp after the flags instead of the
+	// environment variables.
+	Sources []ConfigSource
+
+	// Logger is used by NewApp instead of building one from the log.* keys,
+	// which then, like the log levels, don't apply to it.
+	Logger Logger
+
+	// LoggerFactory builds the logger in place of zap, from the log.* keys
+	// read when creating the app. The log levels don't apply to it.
+	LoggerFactory LoggerFactory
 }
 
 func (ao *AppOptions) getFieldNameAndType(typeVal reflect.StructField) (string, string) {
@@ -71,144 +111,264 @@ func (ao *AppOptions) getFieldNameAndType(typeVal reflect.StructField) (string,
 
 }
 
+func (ao *AppOptions) getFieldKeyList(typeVal reflect.StructField, tag string) []string {
+
+	keys := []string{}
+
+	for _, key := range strings.Split(typeVal.Tag.Get(tag), ",") {
+
+		key = strings.TrimSpace(key)
+
+		if len(key) == 0 {
+			continue
+		}
+
+		if len(ao.Prefix) > 0 {
+			key = strings.Join([]string{ao.Prefix, key}, ".")
+		}
+
+		keys = append(keys, key)
+
+	}
+
+	return keys
+
+}
+
+func (ao *AppOptions) getFieldAliases(typeVal reflect.StructField) []string {
+	return ao.getFieldKeyList(typeVal, "aliases")
+}
+
+func (ao *AppOptions) getFieldDeprecatedKeys(typeVal reflect.StructField) []string {
+	return ao.getFieldKeyList(typeVal, "deprecated")
+}
+
+func (ao *AppOptions) getState() *configState {
+
+	if ao.state == nil {
+		ao.state = newConfigState()
+	}
+
+	return ao.state
+
+}
+
+func (ao *AppOptions) child(prefix string) *AppOptions {
+	return &AppOptions{
+		Source:  ao.Source,
+		FlagSet: ao.FlagSet,
+		Prefix:  prefix,
+		Print:   false,
+		tw:      ao.tw,
+		state:   ao.getState(),
+		visited: ao.visited,
+	}
+}
+
+// configVisit identifies a struct by its address and type, since a struct
+// embedded first has the address of the struct embedding it.
+type configVisit struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// enter reports whether the struct was not configured yet by the current
+// ApplyConfigs, ApplyFlags or ApplyDefaults call, guarding against cycles
+// between components.
+func (ao *AppOptions) enter(e reflect.Value) bool {
+
+	if ao.visited == nil || !e.CanAddr() {
+		return true
+	}
+
+	visit := configVisit{addr: e.Addr().Pointer(), typ: e.Type()}
+
+	if ao.visited[visit] {
+		return false
+	}
+
+	ao.visited[visit] = true
+
+	return true
+
+}
+
+func (ao *AppOptions) getEmbeddedPrefix(key string) string {
+
+	if len(key) == 0 {
+		return ao.Prefix
+	}
+
+	return key
+
+}
+
 func (ao *AppOptions) getFieldUsage(typeVal reflect.StructField) string {
 	return typeVal.Tag.Get("usage")
 }
 
-func (ao *AppOptions) getFieldDefaultValue(typeVal reflect.StructField) string {
+func (ao *AppOptions) getFieldDefaultValue(key string, typeVal reflect.StructField) string {
+
+	if defaults := ao.getState().defaults; defaults != nil && defaults.Has(key) {
+		return defaults.Get(key).StringVal()
+	}
+
 	return typeVal.Tag.Get("default")
+
 }
 
-func (ao *AppOptions) ApplyFlags(keySet any) {
+func (ao *AppOptions) isFieldRequired(typeVal reflect.StructField) bool {
 
-	t := reflect.TypeOf(keySet)
+	switch strings.ToLower(typeVal.Tag.Get("required")) {
+	case "t", "true", "y", "yes":
+		return true
+	}
 
-	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
-		panic("Can only fill flags for struct pointers")
+	return false
+
+}
+
+func (ao *AppOptions) visitConfigFields(e reflect.Value, visit func(ao *AppOptions, typeVal reflect.StructField, fieldVal reflect.Value, key, flagType string)) {
+
+	if !ao.enter(e) {
+		return
 	}
 
-	e := t.Elem()
+	elType := e.Type()
 
 	configType := reflect.TypeOf((*Config)(nil)).Elem()
 
 	for i := 0; i < e.NumField(); i++ {
 
-		typeVal := e.Field(i)
+		typeVal := elType.Field(i)
 
 		key, flagType := ao.getFieldNameAndType(typeVal)
 
+		fieldVal := e.Field(i)
+
+		if typeVal.Anonymous {
+
+			if embeddedVal, ok := embeddedConfigStruct(typeVal.Type, fieldVal, false); ok {
+				ao.child(ao.getEmbeddedPrefix(key)).visitConfigFields(embeddedVal, visit)
+			}
+
+			continue
+
+		}
+
 		if len(key) == 0 {
 			continue
 		}
 
-		if typeVal.Type.Implements(configType) {
+		if typeVal.Type.Implements(configType) || isProtoMessageType(typeVal.Type) {
+			visit(ao, typeVal, fieldVal, key, flagType)
+			continue
+		}
 
-			key := ConvertKeyCase(key, KebabCase)
+		switch {
 
-			usage := strings.Join([]string{
-				"[%s]\n\t",
-				ao.getFieldUsage(typeVal),
-				"\n",
-			}, "")
+		case typeVal.Type.Kind() == reflect.Interface:
 
-			defVal := ao.getFieldDefaultValue(typeVal)
+			if !fieldVal.IsNil() && isStructPtr(fieldVal.Elem().Type()) {
+				ao.child(key).visitConfigFields(fieldVal.Elem().Elem(), visit)
+			}
 
-			switch strings.ToLower(flagType) {
+		case isStructPtr(typeVal.Type):
 
-			case "bool", "boolean":
+			if fieldVal.IsNil() {
+				fieldVal = reflect.New(typeVal.Type.Elem())
+			}
 
-				val := false
+			ao.child(key).visitConfigFields(fieldVal.Elem(), visit)
 
-				switch strings.ToLower(defVal) {
+		case isStructPtrSlice(typeVal.Type):
 
-				case "t", "true", "y", "yes":
-					val = true
+			// The gaps are reported by applyConfigs
+			size, _ := configSliceSize(key, ao.listDefaultKeys(key), fieldVal.Len())
 
-				case "f", "false", "n", "no", "not", "":
-					break
+			for i := 0; i < size; i++ {
 
-				default:
-					panic(fmt.Sprintf("Invalid boolean default value '%s' (error: %s)", key, defVal))
+				elemVal := reflect.New(typeVal.Type.Elem().Elem())
 
+				if i < fieldVal.Len() && !fieldVal.Index(i).IsNil() {
+					elemVal = fieldVal.Index(i)
 				}
 
-				ao.FlagSet.Bool(key, val, fmt.Sprintf(usage, "boolean"))
-
-			case "int64", "int", "integer":
+				ao.child(joinConfigKey(key, strconv.Itoa(i))).visitConfigFields(elemVal.Elem(), visit)
 
-				val := int64(0)
+			}
 
-				if len(defVal) > 0 {
+		case isStructPtrMap(typeVal.Type):
 
-					intVal, err := strconv.ParseInt(defVal, 10, 64)
+			names := newChildKeySet()
 
-					if err != nil {
-						panic(fmt.Errorf("Invalid int64 default value '%s' (error: %s)", key, err))
-					}
-
-					val = intVal
+			if !fieldVal.IsNil() {
+				for _, name := range fieldVal.MapKeys() {
+					names.add(name.String())
 				}
+			}
 
-				ao.FlagSet.Int64(key, val, fmt.Sprintf(usage, "int64"))
-
-			case "float64", "float", "double":
-
-				val := float64(0)
+			for _, name := range ao.listDefaultKeys(key) {
+				names.add(name)
+			}
 
-				if len(defVal) > 0 {
+			for _, name := range names.sorted() {
 
-					floatVal, err := strconv.ParseFloat(defVal, 64)
+				elemVal := reflect.New(typeVal.Type.Elem().Elem())
 
-					if err != nil {
-						panic(fmt.Errorf("Invalid float64 default value '%s' (error: %s)", key, err))
+				if !fieldVal.IsNil() {
+					if existing := fieldVal.MapIndex(reflect.ValueOf(name).Convert(typeVal.Type.Key())); existing.IsValid() && !existing.IsNil() {
+						elemVal = existing
 					}
-
-					val = floatVal
 				}
 
-				ao.FlagSet.Float64(key, val, fmt.Sprintf(usage, "float64"))
+				ao.child(joinConfigKey(key, name)).visitConfigFields(elemVal.Elem(), visit)
+
+			}
+
+		}
 
-			case "duration":
+	}
 
-				val := time.Duration(0)
+}
 
-				if len(defVal) > 0 {
+func (ao *AppOptions) ApplyFlags(keySet any) {
 
-					durationVal, err := time.ParseDuration(defVal)
+	v := reflect.ValueOf(keySet)
 
-					if err != nil {
-						panic(fmt.Errorf("Invalid duration default value '%s' (error: %s)", key, err))
-					}
+	if v.Kind() != reflect.Ptr || v.Type().Elem().Kind() != reflect.Struct {
+		panic("Can only fill flags for struct pointers")
+	}
 
-					val = durationVal
+	if isProtoMessageType(v.Type()) {
+		ao.defineProtoFlags(ao.Prefix, protoDescriptorOf(v.Type()), make(map[protoreflect.FullName]bool))
+		return
+	}
 
-				}
+	if v.IsNil() {
+		v = reflect.New(v.Type().Elem())
+	}
 
-				ao.FlagSet.Duration(key, val, fmt.Sprintf(usage, "duration"))
+	ao.visited = make(map[configVisit]bool)
 
-			case "str", "string":
-				ao.FlagSet.String(key, defVal, fmt.Sprintf(usage, "string"))
+	ao.visitConfigFields(v.Elem(), func(ao *AppOptions, typeVal reflect.StructField, _ reflect.Value, key, flagType string) {
 
-			case "time", "datetime", "date":
-				ao.FlagSet.String(key, defVal, fmt.Sprintf(usage, "datetime"))
+		if isProtoMessageType(typeVal.Type) {
+			ao.defineProtoFlags(key, protoDescriptorOf(typeVal.Type), make(map[protoreflect.FullName]bool))
+			return
+		}
 
-			default:
-				panic(fmt.Errorf("Unable to define the type for flag '%s' (type: %s)", key, flagType))
-			}
+		ao.defineFlag(key, flagType, ao.getFieldUsage(typeVal), ao.getFieldDefaultValue(key, typeVal))
 
-			continue
+		for _, altKey := range ao.getFieldAliases(typeVal) {
+			ao.defineHiddenFlag(altKey, flagType, fmt.Sprintf("Alias of '%s'", key))
 		}
 
-		if typeVal.Type.Kind() == reflect.Ptr && typeVal.Type.Elem().Kind() == reflect.Struct {
-			typeCb := &AppOptions{
-				Source:  ao.Source,
-				FlagSet: ao.FlagSet,
-				Prefix:  key,
-			}
-			typeCb.ApplyFlags(reflect.New(typeVal.Type.Elem()).Interface())
+		for _, altKey := range ao.getFieldDeprecatedKeys(typeVal) {
+			ao.defineHiddenFlag(altKey, flagType, fmt.Sprintf("Deprecated, use '%s' instead", key))
 		}
 
-	}
+	})
 
 }
 
@@ -225,11 +385,39 @@ func (ao *AppOptions) ApplyConfigs(keySet any) {
 		panic("Can only fill configs of struct pointers")
 	}
 
-	e := v.Elem()
+	ao.visited = make(map[configVisit]bool)
+
+	res := &applyResult{}
+
+	if m, ok := keySet.(proto.Message); ok {
+		ao.applyProtoConfig(ao.Prefix, m, res)
+	} else if ao.enter(v.Elem()) {
+		ao.applyConfigs(v.Elem(), res)
+	}
+
+	if ao.Print {
+		fmt.Println("Configuration table:")
+		fmt.Println(ao.tw.Render())
+	}
+
+	if err := res.err(); err != nil {
+		panic(err)
+	}
+
+}
+
+func (ao *AppOptions) applyConfigs(e reflect.Value, res *applyResult) {
+
 	elType := e.Type()
 
 	configType := reflect.TypeOf((*Config)(nil)).Elem()
 
+	if res.reload && e.CanAddr() {
+		if validator, ok := e.Addr().Interface().(ConfigValidator); ok {
+			res.validators = append(res.validators, validator)
+		}
+	}
+
 	for i := 0; i < e.NumField(); i++ {
 
 		fieldVal := e.Field(i)
@@ -242,41 +430,357 @@ func (ao *AppOptions) ApplyConfigs(keySet any) {
 
 		typeVal := elType.Field(i)
 
+		key, _ := ao.getFieldNameAndType(typeVal)
+
+		if typeVal.Anonymous {
+
+			if embeddedVal, ok := embeddedConfigStruct(fieldType, fieldVal, !res.reload); ok && ao.enter(embeddedVal) {
+				ao.child(ao.getEmbeddedPrefix(key)).applyConfigs(embeddedVal, res)
+			}
+
+			continue
+
+		}
+
 		if !typeVal.IsExported() {
 			continue
 		}
 
-		key, _ := ao.getFieldNameAndType(typeVal)
+		if isProtoMessageType(fieldType) {
+
+			if res.reload {
+				continue
+			}
+
+			if fieldVal.IsNil() {
+				fieldVal.Set(reflect.New(fieldType.Elem()))
+			}
+
+			ao.applyProtoConfig(key, fieldVal.Interface().(proto.Message), res)
+			continue
+
+		}
 
 		if fieldType.Implements(configType) {
 
-			cfg := ao.Source.Get(key)
+			reloadable := ao.isFieldReloadable(typeVal)
+
+			if res.reload && !reloadable {
+				continue
+			}
+
+			cfg := ao.lookupConfig(key, ao.getFieldAliases(typeVal), ao.getFieldDeprecatedKeys(typeVal))
+
+			if defVal := typeVal.Tag.Get("default"); !cfg.IsSet() && len(defVal) > 0 {
+				cfg = NewConfig(defVal)
+			}
 
 			if ao.tw != nil {
 				ao.tw.AppendRow(table.Row{key, fmt.Sprintf("%T", cfg), fmt.Sprintf("%+v", cfg)})
 			}
 
+			if ao.isFieldRequired(typeVal) && !cfg.IsSet() {
+				res.missing = append(res.missing, key)
+			}
+
+			secret := ao.isFieldSecret(typeVal, key)
+
+			if res.reload {
+
+				if target, ok := fieldVal.Interface().(*reloadableConfig); ok {
+
+					_, flagType := ao.getFieldNameAndType(typeVal)
+
+					if err := validateConfigValue(key, flagType, cfg); err != nil {
+						res.errs = append(res.errs, err)
+					}
+
+					res.staged = append(res.staged, stagedConfig{target: target, cfg: cfg, key: key, secret: secret})
+
+				}
+
+				continue
+
+			}
+
+			ao.getState().recordConfig(key, effectiveConfigValue(cfg, secret))
+
+			if reloadable {
+				fieldVal.Set(reflect.ValueOf(newReloadableConfig(cfg)))
+				continue
+			}
+
 			fieldVal.Set(reflect.ValueOf(cfg))
 			continue
 		}
 
-		if fieldVal.Kind() == reflect.Ptr && fieldVal.Elem().Kind() == reflect.Struct {
-			fieldCb := &AppOptions{
-				Source:  ao.Source,
-				FlagSet: ao.FlagSet,
-				Prefix:  key,
-				Print:   false,
-				tw:      ao.tw,
+		switch {
+
+		case fieldVal.Kind() == reflect.Interface && !fieldVal.IsNil() && isStructPtr(fieldVal.Elem().Type()):
+
+			if ao.enter(fieldVal.Elem().Elem()) {
+				ao.child(key).applyConfigs(fieldVal.Elem().Elem(), res)
+			}
+
+		case fieldVal.Kind() == reflect.Ptr && fieldVal.Elem().Kind() == reflect.Struct:
+
+			if ao.enter(fieldVal.Elem()) {
+				ao.child(key).applyConfigs(fieldVal.Elem(), res)
+			}
+
+		case len(key) > 0 && isStructPtrSlice(fieldType):
+
+			size := fieldVal.Len()
+
+			if !res.reload {
+
+				grownSize, err := configSliceSize(key, listConfigKeys(ao.Source, key), size)
+				if err != nil {
+					res.errs = append(res.errs, err)
+				}
+
+				if grownSize > size {
+
+					grown := reflect.MakeSlice(fieldType, grownSize, grownSize)
+					reflect.Copy(grown, fieldVal)
+
+					fieldVal.Set(grown)
+					size = grownSize
+
+				}
+
+			}
+
+			for i := 0; i < size; i++ {
+
+				elemVal := fieldVal.Index(i)
+
+				if elemVal.IsNil() {
+
+					if res.reload {
+						continue
+					}
+
+					elemVal.Set(reflect.New(fieldType.Elem().Elem()))
+
+				}
+
+				ao.child(joinConfigKey(key, strconv.Itoa(i))).applyConfigs(elemVal.Elem(), res)
+
+			}
+
+		case len(key) > 0 && isStructPtrMap(fieldType):
+
+			if res.reload {
+
+				iter := fieldVal.MapRange()
+
+				for iter.Next() {
+					if !iter.Value().IsNil() {
+						ao.child(joinConfigKey(key, iter.Key().String())).applyConfigs(iter.Value().Elem(), res)
+					}
+				}
+
+				continue
+
+			}
+
+			if fieldVal.IsNil() {
+				fieldVal.Set(reflect.MakeMap(fieldType))
+			}
+
+			for _, name := range listConfigKeys(ao.Source, key) {
+
+				nameVal := reflect.ValueOf(name).Convert(fieldType.Key())
+
+				if elemVal := fieldVal.MapIndex(nameVal); !elemVal.IsValid() || elemVal.IsNil() {
+					fieldVal.SetMapIndex(nameVal, reflect.New(fieldType.Elem().Elem()))
+				}
+
+			}
+
+			iter := fieldVal.MapRange()
+
+			for iter.Next() {
+				ao.child(joinConfigKey(key, iter.Key().String())).applyConfigs(iter.Value().Elem(), res)
 			}
 
-			fieldCb.ApplyConfigs(fieldVal.Interface())
 		}
 
 	}
 
-	if ao.Print {
-		fmt.Println("Configuration table:")
-		fmt.Println(ao.tw.Render())
+}
+
+type applyResult struct {
+	missing []string
+	errs    []error
+
+	// reload only reads the reloadable fields, staging their values, and
+	// leaves the layout of the structs untouched.
+	reload     bool
+	staged     []stagedConfig
+	validators []ConfigValidator
+}
+
+func (r *applyResult) err() error {
+
+	errs := r.errs
+
+	if len(r.missing) > 0 {
+		errs = append([]error{fmt.Errorf("%w: %s", MissingRequiredConfigError, strings.Join(r.missing, ", "))}, errs...)
+	}
+
+	return errors.Join(errs...)
+
+}
+
+func (ao *AppOptions) listDefaultKeys(key string) []string {
+
+	if defaults := ao.getState().defaults; defaults != nil {
+		return listConfigKeys(defaults, key)
+	}
+
+	return []string{}
+
+}
+
+func (ao *AppOptions) defineFlag(key, flagType, usage, defVal string) {
+
+	key = ConvertKeyCase(key, KebabCase)
+
+	usage = strings.Join([]string{
+		"[%s]\n\t",
+		usage,
+		"\n",
+	}, "")
+
+	switch strings.ToLower(flagType) {
+
+	case "bool", "boolean":
+
+		val := false
+
+		switch strings.ToLower(defVal) {
+
+		case "t", "true", "y", "yes":
+			val = true
+
+		case "f", "false", "n", "no", "not", "":
+			break
+
+		default:
+			panic(fmt.Sprintf("Invalid boolean default value '%s' (error: %s)", key, defVal))
+
+		}
+
+		ao.FlagSet.Bool(key, val, fmt.Sprintf(usage, "boolean"))
+
+	case "int64", "int", "integer":
+
+		val := int64(0)
+
+		if len(defVal) > 0 {
+
+			intVal, err := strconv.ParseInt(defVal, 10, 64)
+
+			if err != nil {
+				panic(fmt.Errorf("Invalid int64 default value '%s' (error: %s)", key, err))
+			}
+
+			val = intVal
+		}
+
+		ao.FlagSet.Int64(key, val, fmt.Sprintf(usage, "int64"))
+
+	case "float64", "float", "double":
+
+		val := float64(0)
+
+		if len(defVal) > 0 {
+
+			floatVal, err := strconv.ParseFloat(defVal, 64)
+
+			if err != nil {
+				panic(fmt.Errorf("Invalid float64 default value '%s' (error: %s)", key, err))
+			}
+
+			val = floatVal
+		}
+
+		ao.FlagSet.Float64(key, val, fmt.Sprintf(usage, "float64"))
+
+	case "duration":
+
+		val := time.Duration(0)
+
+		if len(defVal) > 0 {
+
+			durationVal, err := time.ParseDuration(defVal)
+
+			if err != nil {
+				panic(fmt.Errorf("Invalid duration default value '%s' (error: %s)", key, err))
+			}
+
+			val = durationVal
+
+		}
+
+		ao.FlagSet.Duration(key, val, fmt.Sprintf(usage, "duration"))
+
+	case "str", "string":
+		ao.FlagSet.String(key, defVal, fmt.Sprintf(usage, "string"))
+
+	case "time", "datetime", "date":
+		ao.FlagSet.String(key, defVal, fmt.Sprintf(usage, "datetime"))
+
+	default:
+		panic(fmt.Errorf("Unable to define the type for flag '%s' (type: %s)", key, flagType))
+	}
+
+}
+
+func (ao *AppOptions) defineHiddenFlag(key, flagType, usage string) {
+
+	ao.defineFlag(key, flagType, usage, "")
+
+	ao.getState().hideFlag(ao.FlagSet, ConvertKeyCase(key, KebabCase))
+
+}
+
+// hasConfig reports whether a source other than the defaults has the key, so
+// the aliases and deprecated keys are looked up before falling back to the
+// defaults.
+func (ao *AppOptions) hasConfig(key string) bool {
+
+	defaults := ao.getState().defaults
+
+	if composite, ok := ao.Source.(*compositeSource); ok && defaults != nil {
+		return composite.hasExcept(key, defaults)
 	}
 
+	return ao.Source.Has(key)
+
+}
+
+func (ao *AppOptions) lookupConfig(key string, aliases []string, deprecated []string) Config {
+
+	if ao.hasConfig(key) {
+		return ao.Source.Get(key)
+	}
+
+	for _, altKey := range aliases {
+		if ao.hasConfig(altKey) {
+			return ao.Source.Get(altKey)
+		}
+	}
+
+	for _, altKey := range deprecated {
+		if ao.hasConfig(altKey) {
+			ao.getState().warnDeprecated(altKey, key)
+			return ao.Source.Get(altKey)
+		}
+	}
+
+	return ao.Source.Get(key)
+
 }
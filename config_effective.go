@@ -0,0 +1,136 @@
+package app
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const redactedConfigValue = "[REDACTED]"
+
+// secretKeyWords flag the keys whose values are redacted from the effective
+// configuration even without the secret tag.
+var secretKeyWords = []string{"password", "passwd", "secret", "token", "credential", "private", "apikey"}
+
+func isSecretKey(key string) bool {
+
+	lower := strings.ToLower(strings.NewReplacer(".", "", "-", "", "_", "").Replace(key))
+
+	for _, word := range secretKeyWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+func (ao *AppOptions) isFieldSecret(typeVal reflect.StructField, key string) bool {
+
+	switch strings.ToLower(typeVal.Tag.Get("secret")) {
+	case "t", "true", "y", "yes":
+		return true
+	case "f", "false", "n", "no", "not":
+		return false
+	}
+
+	return isSecretKey(key)
+
+}
+
+func effectiveConfigValue(cfg Config, secret bool) any {
+
+	if cfg == nil || !cfg.IsSet() {
+		return nil
+	}
+
+	if secret {
+		return redactedConfigValue
+	}
+
+	return cfg.StringVal()
+
+}
+
+func (s *configState) recordConfig(key string, val any) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.effective == nil {
+		s.effective = make(map[string]any)
+	}
+
+	s.effective[key] = val
+
+}
+
+func (s *configState) recordProtoConfig(key string, m proto.Message) {
+
+	raw, err := protojson.Marshal(m)
+	if err != nil {
+		return
+	}
+
+	var val any
+
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return
+	}
+
+	s.recordConfig(key, redactJsonSecrets(val))
+
+}
+
+func redactJsonSecrets(val any) any {
+
+	switch typedVal := val.(type) {
+
+	case map[string]any:
+
+		for k, v := range typedVal {
+
+			if isSecretKey(k) {
+				typedVal[k] = redactedConfigValue
+				continue
+			}
+
+			typedVal[k] = redactJsonSecrets(v)
+
+		}
+
+	case []any:
+
+		for i, v := range typedVal {
+			typedVal[i] = redactJsonSecrets(v)
+		}
+
+	}
+
+	return val
+
+}
+
+// EffectiveConfig returns the values applied by ApplyConfigs and
+// ReloadConfigs keyed by configuration key, with the values of the fields
+// tagged with secret:"true", or whose key looks like a secret, redacted.
+func (ao *AppOptions) EffectiveConfig() map[string]any {
+
+	s := ao.getState()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	effective := make(map[string]any, len(s.effective))
+
+	for key, val := range s.effective {
+		effective[key] = val
+	}
+
+	return effective
+
+}
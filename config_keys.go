@@ -0,0 +1,236 @@
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type childKeySet struct {
+	keys []string
+	seen map[string]bool
+}
+
+func newChildKeySet() *childKeySet {
+	return &childKeySet{
+		keys: []string{},
+		seen: make(map[string]bool),
+	}
+}
+
+func (c *childKeySet) add(child string) {
+
+	if len(child) == 0 || c.seen[child] {
+		return
+	}
+
+	c.seen[child] = true
+	c.keys = append(c.keys, child)
+
+}
+
+// addFromKey adds the first key segment after prefix, if the key is under it.
+func (c *childKeySet) addFromKey(prefix, key string) {
+
+	if len(prefix) > 0 {
+
+		if !strings.HasPrefix(key, prefix+".") {
+			return
+		}
+
+		key = key[len(prefix)+1:]
+
+	}
+
+	if sep := strings.Index(key, "."); sep >= 0 {
+		key = key[:sep]
+	}
+
+	c.add(key)
+
+}
+
+func (c *childKeySet) sorted() []string {
+
+	sort.Strings(c.keys)
+
+	return c.keys
+
+}
+
+func joinConfigKey(parent, child string) string {
+
+	if len(parent) == 0 {
+		return child
+	}
+
+	return strings.Join([]string{parent, child}, ".")
+
+}
+
+func isStructPtr(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct
+}
+
+func isStructPtrSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && isStructPtr(t.Elem())
+}
+
+func isStructPtrMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && isStructPtr(t.Elem())
+}
+
+func isInjectorType(t reflect.Type) bool {
+
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	injectorType := reflect.TypeOf(Injector[any]{})
+
+	return t.PkgPath() == injectorType.PkgPath() && strings.HasPrefix(t.Name(), "Injector[")
+
+}
+
+func hasConfigFields(t reflect.Type, seen map[reflect.Type]bool) bool {
+
+	if seen[t] {
+		return false
+	}
+
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+
+		field := t.Field(i)
+
+		if len(field.Tag.Get("config")) > 0 {
+			return true
+		}
+
+		if !field.Anonymous || isInjectorType(field.Type) {
+			continue
+		}
+
+		switch {
+
+		case field.Type.Kind() == reflect.Struct:
+
+			if hasConfigFields(field.Type, seen) {
+				return true
+			}
+
+		case isStructPtr(field.Type):
+
+			if hasConfigFields(field.Type.Elem(), seen) {
+				return true
+			}
+
+		}
+
+	}
+
+	return false
+
+}
+
+// embeddedConfigStruct returns the struct value behind an embedded field
+// declaring configurations, allocating nil pointers when alloc is set.
+func embeddedConfigStruct(t reflect.Type, fieldVal reflect.Value, alloc bool) (reflect.Value, bool) {
+
+	switch {
+
+	case t.Kind() == reflect.Struct:
+
+		if !hasConfigFields(t, make(map[reflect.Type]bool)) {
+			return reflect.Value{}, false
+		}
+
+		return fieldVal, true
+
+	case isStructPtr(t):
+
+		if isInjectorType(t) || !hasConfigFields(t.Elem(), make(map[reflect.Type]bool)) {
+			return reflect.Value{}, false
+		}
+
+		if fieldVal.IsNil() {
+
+			if !alloc {
+				return reflect.New(t.Elem()).Elem(), true
+			}
+
+			if !fieldVal.CanSet() {
+				return reflect.Value{}, false
+			}
+
+			fieldVal.Set(reflect.New(t.Elem()))
+
+		}
+
+		return fieldVal.Elem(), true
+
+	}
+
+	return reflect.Value{}, false
+
+}
+
+func childKeysOf[V any](configs map[string]V, prefix string) []string {
+
+	children := newChildKeySet()
+
+	for key := range configs {
+		children.addFromKey(prefix, key)
+	}
+
+	return children.sorted()
+
+}
+
+func listConfigKeys(src ConfigSource, prefix string) []string {
+
+	if lister, ok := src.(ConfigKeyLister); ok {
+		return lister.Keys(prefix)
+	}
+
+	return []string{}
+
+}
+
+// configSliceSize returns the size of a slice of the given size grown to hold
+// the indexes of the keys. The indexes past the slice must follow each other,
+// so a key like UPSTREAMS_100000000_URL is rejected instead of allocating a
+// slice that large.
+func configSliceSize(key string, keys []string, size int) (int, error) {
+
+	indexes := make(map[int]bool)
+	max := -1
+
+	for _, k := range keys {
+
+		if i, err := strconv.Atoi(k); err == nil && i >= 0 {
+
+			indexes[i] = true
+
+			if i > max {
+				max = i
+			}
+
+		}
+
+	}
+
+	for ; size <= max; size++ {
+
+		if !indexes[size] {
+			return size, fmt.Errorf("%w: %s has the index %d but not %d", ConfigIndexGapError, key, max, size)
+		}
+
+	}
+
+	return size, nil
+
+}
@@ -0,0 +1,209 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ConfigValidator is implemented by configuration structs checking their
+// values as a whole. It's called by ReloadConfigs once the new values are set,
+// which are reverted when it fails.
+type ConfigValidator interface {
+	ValidateConfig() error
+}
+
+type configBox struct {
+	Config
+}
+
+// reloadableConfig is assigned to the fields tagged with reloadable:"true",
+// so the values can be swapped by ReloadConfigs while they're being read.
+type reloadableConfig struct {
+	val atomic.Value
+}
+
+func newReloadableConfig(cfg Config) *reloadableConfig {
+
+	r := &reloadableConfig{}
+	r.set(cfg)
+
+	return r
+
+}
+
+func (r *reloadableConfig) get() Config {
+	return r.val.Load().(configBox).Config
+}
+
+func (r *reloadableConfig) set(cfg Config) {
+	r.val.Store(configBox{cfg})
+}
+
+func (r *reloadableConfig) IsSet() bool {
+	return r.get().IsSet()
+}
+
+func (r *reloadableConfig) StringVal() string {
+	return r.get().StringVal()
+}
+
+func (r *reloadableConfig) Int64Val() int64 {
+	return r.get().Int64Val()
+}
+
+func (r *reloadableConfig) Float64Val() float64 {
+	return r.get().Float64Val()
+}
+
+func (r *reloadableConfig) StringSliceVal() []string {
+	return r.get().StringSliceVal()
+}
+
+func (r *reloadableConfig) DurationVal() time.Duration {
+	return r.get().DurationVal()
+}
+
+func (r *reloadableConfig) TimeVal() time.Time {
+	return r.get().TimeVal()
+}
+
+func (r *reloadableConfig) BoolVal() bool {
+	return r.get().BoolVal()
+}
+
+func (r *reloadableConfig) InterfaceVal() interface{} {
+	return r.get().InterfaceVal()
+}
+
+func (r *reloadableConfig) String() string {
+	return fmt.Sprintf("%+v", r.get())
+}
+
+type stagedConfig struct {
+	target *reloadableConfig
+	cfg    Config
+	key    string
+	secret bool
+}
+
+func (ao *AppOptions) isFieldReloadable(typeVal reflect.StructField) bool {
+
+	switch strings.ToLower(typeVal.Tag.Get("reloadable")) {
+	case "t", "true", "y", "yes":
+		return true
+	}
+
+	return false
+
+}
+
+// ReloadConfigs reads again from Source the fields tagged with
+// reloadable:"true" of the key sets already configured by ApplyConfigs. The
+// new values are only kept when they're all valid, otherwise the previous ones
+// remain and the validation errors are returned.
+func (ao *AppOptions) ReloadConfigs(keySets ...any) error {
+
+	ao.tw = nil
+	ao.visited = make(map[configVisit]bool)
+
+	res := &applyResult{reload: true}
+
+	for _, keySet := range keySets {
+
+		v := reflect.ValueOf(keySet)
+
+		if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct || isProtoMessageType(v.Type()) {
+			continue
+		}
+
+		if ao.enter(v.Elem()) {
+			ao.applyConfigs(v.Elem(), res)
+		}
+
+	}
+
+	if err := res.err(); err != nil {
+		return err
+	}
+
+	previous := make([]Config, len(res.staged))
+
+	for i, staged := range res.staged {
+		previous[i] = staged.target.get()
+		staged.target.set(staged.cfg)
+	}
+
+	errs := []error{}
+
+	for _, validator := range res.validators {
+		if err := validator.ValidateConfig(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+
+		for i, staged := range res.staged {
+			staged.target.set(previous[i])
+		}
+
+		return errors.Join(errs...)
+
+	}
+
+	for _, staged := range res.staged {
+		ao.getState().recordConfig(staged.key, effectiveConfigValue(staged.cfg, staged.secret))
+	}
+
+	return nil
+
+}
+
+// validateConfigValue checks that a value read for reloading can be parsed as
+// the type of the field, since the Config getters silently return zero values.
+func validateConfigValue(key, flagType string, cfg Config) error {
+
+	if !cfg.IsSet() {
+		return nil
+	}
+
+	raw := cfg.StringVal()
+
+	var err error
+
+	switch flagType {
+
+	case "bool", "boolean":
+
+		switch strings.ToLower(raw) {
+		case "t", "true", "y", "yes", "f", "false", "n", "no", "not":
+		default:
+			err = fmt.Errorf("invalid boolean '%s'", raw)
+		}
+
+	case "int64", "int", "integer":
+		_, err = strconv.ParseInt(raw, 10, 64)
+
+	case "float64", "float", "double":
+		_, err = strconv.ParseFloat(raw, 64)
+
+	case "duration":
+		_, err = time.ParseDuration(raw)
+
+	case "time", "datetime", "date":
+		_, err = time.Parse(time.RFC3339, raw)
+
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid value for '%s': %w", key, err)
+	}
+
+	return nil
+
+}
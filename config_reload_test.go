@@ -0,0 +1,77 @@
+package app_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	app "github.com/protomesh/go-app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reloadedRoot struct {
+	*app.Injector[*reloadedRoot]
+
+	Limit   app.Config `config:"store.limit,int" reloadable:"true"`
+	Name    app.Config `config:"store.name,str" reloadable:"true"`
+	Address app.Config `config:"store.addr,str"`
+}
+
+func (r *reloadedRoot) ValidateConfig() error {
+
+	if r.Name.StringVal() == "forbidden" {
+		return errors.New("forbidden name")
+	}
+
+	return nil
+
+}
+
+func TestAppReload(t *testing.T) {
+
+	filePath := filepath.Join(t.TempDir(), "config.yaml")
+
+	writeConfig := func(content string) {
+		assert.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+	}
+
+	writeConfig("store:\n  limit: 10\n  name: pets\n  addr: localhost:80\n")
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-config-file", filePath}
+
+	deps := &reloadedRoot{}
+
+	a := app.NewApp(deps, opts)
+	defer a.Close()
+
+	limit := deps.Limit
+
+	writeConfig("store:\n  limit: 20\n  name: animals\n  addr: localhost:81\n")
+
+	assert.NoError(t, a.Reload())
+	assert.EqualValues(t, 20, deps.Limit.Int64Val())
+	assert.EqualValues(t, 20, limit.Int64Val())
+	assert.Equal(t, "animals", deps.Name.StringVal())
+	assert.Equal(t, "localhost:80", deps.Address.StringVal())
+
+	writeConfig("store:\n  limit: many\n  name: other\n")
+
+	assert.ErrorContains(t, a.Reload(), "invalid value for 'store.limit'")
+	assert.EqualValues(t, 20, deps.Limit.Int64Val())
+	assert.Equal(t, "animals", deps.Name.StringVal())
+
+	writeConfig("store:\n  limit: 30\n  name: forbidden\n")
+
+	assert.ErrorContains(t, a.Reload(), "forbidden name")
+	assert.EqualValues(t, 20, deps.Limit.Int64Val())
+	assert.Equal(t, "animals", deps.Name.StringVal())
+
+	writeConfig("store:\n  limit: 40\n  name: animals\nlog:\n  level: loud\n")
+
+	assert.ErrorContains(t, a.Reload(), "invalid log level 'loud'")
+	assert.EqualValues(t, 20, deps.Limit.Int64Val())
+
+}
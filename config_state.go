@@ -0,0 +1,128 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"sync"
+)
+
+type deprecatedKeyUse struct {
+	oldKey string
+	newKey string
+}
+
+// configState is shared between an AppOptions and all the nested options
+// created while walking the configuration tree.
+type configState struct {
+	mu sync.Mutex
+
+	hiddenFlags map[*flag.FlagSet]map[string]bool
+	defaults    ConfigSource
+
+	logger         Logger
+	warned         map[string]bool
+	pendingWarning []deprecatedKeyUse
+
+	effective map[string]any
+}
+
+func newConfigState() *configState {
+	return &configState{
+		hiddenFlags: make(map[*flag.FlagSet]map[string]bool),
+		warned:      make(map[string]bool),
+	}
+}
+
+func (s *configState) hideFlag(fs *flag.FlagSet, name string) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hidden, ok := s.hiddenFlags[fs]
+	if !ok {
+
+		hidden = make(map[string]bool)
+		s.hiddenFlags[fs] = hidden
+
+		fs.Usage = func() {
+			s.printVisibleDefaults(fs)
+		}
+
+	}
+
+	hidden[name] = true
+
+}
+
+func (s *configState) printVisibleDefaults(fs *flag.FlagSet) {
+
+	s.mu.Lock()
+	hidden := s.hiddenFlags[fs]
+	s.mu.Unlock()
+
+	out := fs.Output()
+
+	if len(fs.Name()) == 0 {
+		fmt.Fprintf(out, "Usage:\n")
+	} else {
+		fmt.Fprintf(out, "Usage of %s:\n", fs.Name())
+	}
+
+	visible := flag.NewFlagSet(fs.Name(), flag.ContinueOnError)
+	visible.SetOutput(out)
+
+	fs.VisitAll(func(f *flag.Flag) {
+
+		if hidden[f.Name] {
+			return
+		}
+
+		visible.Var(f.Value, f.Name, f.Usage)
+		visible.Lookup(f.Name).DefValue = f.DefValue
+
+	})
+
+	visible.PrintDefaults()
+
+}
+
+func (s *configState) warnDeprecated(oldKey, newKey string) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.warned[oldKey] {
+		return
+	}
+
+	s.warned[oldKey] = true
+
+	use := deprecatedKeyUse{oldKey, newKey}
+
+	if s.logger == nil {
+		s.pendingWarning = append(s.pendingWarning, use)
+		return
+	}
+
+	s.logDeprecated(use)
+
+}
+
+func (s *configState) setLogger(logger Logger) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logger = logger
+
+	for _, use := range s.pendingWarning {
+		s.logDeprecated(use)
+	}
+
+	s.pendingWarning = nil
+
+}
+
+func (s *configState) logDeprecated(use deprecatedKeyUse) {
+	s.logger.Warn("Deprecated configuration key in use", "key", use.oldKey, "replacement", use.newKey)
+}
@@ -0,0 +1,463 @@
+package app_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	app "github.com/protomesh/go-app"
+	"github.com/protomesh/go-app/apptest"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+type aliasedConfig struct {
+	Addr    app.Config `config:"server.addr,str" deprecated:"listen.addr" usage:"Server address"`
+	Timeout app.Config `config:"server.timeout,duration" aliases:"timeout,server.deadline"`
+}
+
+func newTestOptions(t *testing.T) (*app.AppOptions, *flag.FlagSet) {
+
+	fs := flag.NewFlagSet(t.Name(), flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+
+	return &app.AppOptions{FlagSet: fs}, fs
+
+}
+
+func loadTestSource(t *testing.T, fs *flag.FlagSet, args ...string) app.ConfigSource {
+
+	assert.NoError(t, fs.Parse(args))
+
+	src := app.NewCompositeSource(
+		app.NewFlagSource(app.JsonPathCase, fs),
+		app.NewEnvSource(app.JsonPathCase),
+	)
+
+	assert.NoError(t, src.Load())
+
+	return src
+
+}
+
+func TestApplyConfigsDeprecatedAndAliases(t *testing.T) {
+
+	opts, fs := newTestOptions(t)
+
+	cfg := &aliasedConfig{}
+
+	opts.ApplyFlags(cfg)
+
+	t.Setenv("SERVER_DEADLINE", "5s")
+
+	opts.Source = loadTestSource(t, fs, "-listen-addr", "localhost:8080")
+	opts.ApplyConfigs(cfg)
+
+	assert.Equal(t, "localhost:8080", cfg.Addr.StringVal())
+	assert.Equal(t, "5s", cfg.Timeout.DurationVal().String())
+
+}
+
+func TestApplyConfigsPrefersCurrentKey(t *testing.T) {
+
+	opts, fs := newTestOptions(t)
+
+	cfg := &aliasedConfig{}
+
+	opts.ApplyFlags(cfg)
+
+	t.Setenv("LISTEN_ADDR", "old:8080")
+
+	opts.Source = loadTestSource(t, fs, "-server-addr", "new:8080")
+	opts.ApplyConfigs(cfg)
+
+	assert.Equal(t, "new:8080", cfg.Addr.StringVal())
+
+}
+
+type renamedDefaultsRoot struct {
+	*app.Injector[*renamedDefaultsRoot]
+
+	Addr app.Config `config:"server.addr,str" deprecated:"listen.addr" aliases:"addr" default:"0.0.0.0:80"`
+}
+
+func TestApplyConfigsRenamedKeysBeforeDefaults(t *testing.T) {
+
+	newApp := func(args ...string) (*renamedDefaultsRoot, *apptest.Logger) {
+
+		log := apptest.NewLogger()
+
+		opts, _ := newTestOptions(t)
+		opts.Args = args
+		opts.Logger = log
+
+		deps := &renamedDefaultsRoot{}
+
+		a := app.NewApp(deps, opts)
+		t.Cleanup(a.Close)
+
+		return deps, log
+
+	}
+
+	deps, _ := newApp()
+	assert.Equal(t, "0.0.0.0:80", deps.Addr.StringVal())
+
+	deps, _ = newApp("-addr", "alias:8080")
+	assert.Equal(t, "alias:8080", deps.Addr.StringVal())
+
+	t.Setenv("LISTEN_ADDR", "deprecated:8080")
+
+	deps, log := newApp()
+	assert.Equal(t, "deprecated:8080", deps.Addr.StringVal())
+	log.AssertLogged(t, app.LogLevelWarn, "Deprecated configuration key in use", "key", "listen.addr", "replacement", "server.addr")
+
+}
+
+func TestApplyFlagsHidesAlternativeKeys(t *testing.T) {
+
+	opts, fs := newTestOptions(t)
+
+	out := &bytes.Buffer{}
+	fs.SetOutput(out)
+
+	opts.ApplyFlags(&aliasedConfig{})
+
+	fs.Usage()
+
+	assert.Contains(t, out.String(), "-server-addr")
+	assert.NotContains(t, out.String(), "-listen-addr")
+	assert.NotContains(t, out.String(), "-server-deadline")
+	assert.NotNil(t, fs.Lookup("listen-addr"))
+
+}
+
+type defaultsConfig struct {
+	Name    app.Config `config:"store.name,str" default:"Animaland"`
+	Port    app.Config `config:"store.port,int" default:"8080"`
+	Timeout app.Config `config:"store.timeout,duration"`
+}
+
+func TestApplyDefaultsWithoutFlags(t *testing.T) {
+
+	cfg := &defaultsConfig{}
+
+	opts := &app.AppOptions{
+		Defaults: &defaultsConfig{
+			Port:    app.NewConfig(int64(9090)),
+			Timeout: app.NewConfig(5 * time.Second),
+		},
+	}
+
+	opts.Source = app.NewCompositeSource(
+		app.NewEnvSource(app.JsonPathCase),
+		opts.ApplyDefaults(cfg),
+	)
+	assert.NoError(t, opts.Source.Load())
+
+	opts.ApplyConfigs(cfg)
+
+	assert.Equal(t, "Animaland", cfg.Name.StringVal())
+	assert.Equal(t, int64(9090), cfg.Port.Int64Val())
+	assert.Equal(t, 5*time.Second, cfg.Timeout.DurationVal())
+
+}
+
+func TestApplyDefaultsLowestPrecedence(t *testing.T) {
+
+	opts, fs := newTestOptions(t)
+
+	cfg := &defaultsConfig{}
+
+	defaults := opts.ApplyDefaults(cfg)
+
+	opts.ApplyFlags(cfg)
+
+	assert.Equal(t, "Animaland", fs.Lookup("store-name").DefValue)
+
+	t.Setenv("STORE_NAME", "Petland")
+
+	opts.Source = app.NewCompositeSource(loadTestSource(t, fs), defaults)
+	opts.ApplyConfigs(cfg)
+
+	assert.Equal(t, "Petland", cfg.Name.StringVal())
+	assert.Equal(t, int64(8080), cfg.Port.Int64Val())
+
+}
+
+type nestedDefaultsConfig struct {
+	Mode app.Config `config:"tls,str" default:"auto"`
+	Cert app.Config `config:"tls.cert,str" default:"cert.pem"`
+}
+
+func TestApplyDefaultsKeepsNestedKeys(t *testing.T) {
+
+	opts := &app.AppOptions{}
+
+	cfg := &nestedDefaultsConfig{}
+
+	opts.Source = opts.ApplyDefaults(cfg)
+	assert.NoError(t, opts.Source.Load())
+
+	opts.ApplyConfigs(cfg)
+
+	assert.Equal(t, "auto", cfg.Mode.StringVal())
+	assert.Equal(t, "cert.pem", cfg.Cert.StringVal())
+
+}
+
+type requiredConfig struct {
+	Database app.Config `config:"database.url,str" required:"true"`
+}
+
+func TestApplyConfigsRequired(t *testing.T) {
+
+	opts, fs := newTestOptions(t)
+
+	cfg := &requiredConfig{}
+
+	opts.ApplyFlags(cfg)
+	opts.Source = loadTestSource(t, fs)
+
+	assert.PanicsWithError(t, "MissingRequiredConfig: database.url", func() {
+		opts.ApplyConfigs(cfg)
+	})
+
+	opts.Source = loadTestSource(t, fs, "-database-url", "postgres://localhost")
+
+	assert.NotPanics(t, func() {
+		opts.ApplyConfigs(cfg)
+	})
+
+}
+
+type upstreamConfig struct {
+	URL     app.Config `config:"url,str" required:"true"`
+	Timeout app.Config `config:"timeout,duration" default:"5s"`
+}
+
+type upstreamsConfig struct {
+	Upstreams []*upstreamConfig          `config:"upstreams"`
+	Named     map[string]*upstreamConfig `config:"named.upstreams"`
+}
+
+func TestApplyConfigsStructSlicesAndMaps(t *testing.T) {
+
+	filePath := filepath.Join(t.TempDir(), "config.yaml")
+
+	assert.NoError(t, os.WriteFile(filePath, []byte(`
+upstreams:
+  - url: http://first
+  - url: http://second
+    timeout: 1s
+named:
+  upstreams:
+    primary:
+      url: http://primary
+`), 0o644))
+
+	t.Setenv("UPSTREAMS_1_URL", "http://overridden")
+	t.Setenv("UPSTREAMS_2_URL", "http://third")
+	t.Setenv("NAMED_UPSTREAMS_SECONDARY_URL", "http://secondary")
+
+	opts := &app.AppOptions{
+		Source: app.NewCompositeSource(
+			app.NewEnvSource(app.JsonPathCase),
+			app.NewFileSource(filePath),
+		),
+	}
+	assert.NoError(t, opts.Source.Load())
+
+	cfg := &upstreamsConfig{}
+
+	opts.ApplyConfigs(cfg)
+
+	if assert.Len(t, cfg.Upstreams, 3) {
+		assert.Equal(t, "http://first", cfg.Upstreams[0].URL.StringVal())
+		assert.Equal(t, 5*time.Second, cfg.Upstreams[0].Timeout.DurationVal())
+		assert.Equal(t, "http://overridden", cfg.Upstreams[1].URL.StringVal())
+		assert.Equal(t, time.Second, cfg.Upstreams[1].Timeout.DurationVal())
+		assert.Equal(t, "http://third", cfg.Upstreams[2].URL.StringVal())
+	}
+
+	if assert.Len(t, cfg.Named, 2) {
+		assert.Equal(t, "http://primary", cfg.Named["primary"].URL.StringVal())
+		assert.Equal(t, "http://secondary", cfg.Named["secondary"].URL.StringVal())
+	}
+
+}
+
+func TestApplyConfigsStructSliceIndexGap(t *testing.T) {
+
+	t.Setenv("UPSTREAMS_0_URL", "http://first")
+	t.Setenv("UPSTREAMS_100000000_URL", "http://far")
+
+	opts := &app.AppOptions{Source: app.NewEnvSource(app.JsonPathCase)}
+	assert.NoError(t, opts.Source.Load())
+
+	cfg := &upstreamsConfig{}
+
+	assert.PanicsWithError(t, "ConfigIndexGap: upstreams has the index 100000000 but not 1", func() {
+		opts.ApplyConfigs(cfg)
+	})
+
+	assert.Len(t, cfg.Upstreams, 1)
+
+}
+
+func TestApplyFlagsStructSliceElements(t *testing.T) {
+
+	opts, fs := newTestOptions(t)
+
+	cfg := &upstreamsConfig{
+		Upstreams: []*upstreamConfig{{}},
+	}
+
+	opts.ApplyFlags(cfg)
+
+	opts.Source = loadTestSource(t, fs, "-upstreams-0-url", "http://flag")
+	opts.ApplyConfigs(cfg)
+
+	if assert.Len(t, cfg.Upstreams, 1) {
+		assert.Equal(t, "http://flag", cfg.Upstreams[0].URL.StringVal())
+	}
+
+}
+
+type ListenerMixin struct {
+	Addr app.Config `config:"listen.addr,str" default:":8080"`
+}
+
+type TLSMixin struct {
+	Cert app.Config `config:"cert,str"`
+}
+
+type Component interface {
+	Name() string
+}
+
+type namedComponent struct {
+	Label app.Config `config:"label,str"`
+}
+
+func (n *namedComponent) Name() string {
+	return n.Label.StringVal()
+}
+
+type mixinConfig struct {
+	ListenerMixin
+	*TLSMixin `config:"tls"`
+
+	Component Component `config:"component"`
+}
+
+func TestApplyConfigsEmbeddedAndInterfaces(t *testing.T) {
+
+	opts, fs := newTestOptions(t)
+
+	cfg := &mixinConfig{
+		Component: &namedComponent{},
+	}
+
+	opts.ApplyFlags(cfg)
+
+	assert.NotNil(t, fs.Lookup("listen-addr"))
+	assert.NotNil(t, fs.Lookup("tls-cert"))
+	assert.NotNil(t, fs.Lookup("component-label"))
+
+	opts.Source = loadTestSource(t, fs, "-tls-cert", "/etc/cert.pem", "-component-label", "mixin")
+	opts.ApplyConfigs(cfg)
+
+	assert.Equal(t, ":8080", cfg.Addr.StringVal())
+	assert.Equal(t, "/etc/cert.pem", cfg.Cert.StringVal())
+	assert.Equal(t, "mixin", cfg.Component.Name())
+
+}
+
+type cyclicServer struct {
+	ListenerMixin
+
+	Name   app.Config    `config:"name,str" default:"pets"`
+	Client *cyclicClient `config:"client"`
+}
+
+type cyclicClient struct {
+	ListenerMixin
+
+	Retries app.Config    `config:"retries,int" default:"3"`
+	Server  *cyclicServer `config:"server"`
+}
+
+func TestApplyConfigsCyclicComponents(t *testing.T) {
+
+	opts, fs := newTestOptions(t)
+
+	cfg := &cyclicServer{Client: &cyclicClient{}}
+	cfg.Client.Server = cfg
+
+	opts.ApplyFlags(cfg)
+
+	assert.NotNil(t, fs.Lookup("listen-addr"))
+	assert.NotNil(t, fs.Lookup("client-retries"))
+	assert.Nil(t, fs.Lookup("client-server-name"))
+
+	defaults := opts.ApplyDefaults(cfg)
+
+	opts.Source = app.NewCompositeSource(loadTestSource(t, fs, "-name", "shop"), defaults)
+	opts.ApplyConfigs(cfg)
+
+	assert.Equal(t, ":8080", cfg.Addr.StringVal())
+	assert.Equal(t, "shop", cfg.Name.StringVal())
+	assert.Equal(t, int64(3), cfg.Client.Retries.Int64Val())
+	assert.Equal(t, ":8080", cfg.Client.Addr.StringVal())
+
+}
+
+type protoConfig struct {
+	API *apipb.Api `config:"api"`
+}
+
+func TestApplyConfigsProtoMessage(t *testing.T) {
+
+	filePath := filepath.Join(t.TempDir(), "config.yaml")
+
+	assert.NoError(t, os.WriteFile(filePath, []byte(`
+api:
+  name: petstore.v1.PetStore
+  version: v1
+  methods:
+    - name: ListPets
+`), 0o644))
+
+	opts, fs := newTestOptions(t)
+
+	cfg := &protoConfig{}
+
+	opts.ApplyFlags(cfg)
+
+	assert.NotNil(t, fs.Lookup("api-syntax"))
+	assert.NotNil(t, fs.Lookup("api-source-context-file-name"))
+
+	t.Setenv("API_VERSION", "v2")
+
+	opts.Source = app.NewCompositeSource(
+		loadTestSource(t, fs, "-api-syntax", "syntax_proto3", "-api-source-context-file-name", "petstore.proto"),
+		app.NewFileSource(filePath),
+	)
+	assert.NoError(t, opts.Source.Load())
+
+	opts.ApplyConfigs(cfg)
+
+	assert.Equal(t, "petstore.v1.PetStore", cfg.API.GetName())
+	assert.Equal(t, "v2", cfg.API.GetVersion())
+	assert.Equal(t, typepb.Syntax_SYNTAX_PROTO3, cfg.API.GetSyntax())
+	assert.Equal(t, "petstore.proto", cfg.API.GetSourceContext().GetFileName())
+	assert.Len(t, cfg.API.GetMethods(), 1)
+	assert.Nil(t, cfg.API.GetOptions())
+
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"reflect"
+)
+
+type defaultsSource struct {
+	configs map[string]Config
+}
+
+func (d *defaultsSource) Load() error {
+	return nil
+}
+
+func (d *defaultsSource) Get(k string) Config {
+
+	if c, ok := d.configs[k]; ok {
+		return c
+	}
+
+	return EmptyConfig()
+
+}
+
+func (d *defaultsSource) Keys(prefix string) []string {
+	return childKeysOf(d.configs, prefix)
+}
+
+func (d *defaultsSource) Has(k string) bool {
+
+	_, ok := d.configs[k]
+
+	return ok
+
+}
+
+// ApplyDefaults collects the `default` tags of the key sets, overridden by the
+// set Config fields of Defaults, into a source meant to be consulted last.
+func (ao *AppOptions) ApplyDefaults(keySets ...any) ConfigSource {
+
+	src := &defaultsSource{
+		configs: make(map[string]Config),
+	}
+
+	collect := func(keySet any, fromTags bool) {
+
+		v := reflect.ValueOf(keySet)
+
+		if v.Kind() != reflect.Ptr || v.Type().Elem().Kind() != reflect.Struct {
+			panic("Can only collect defaults of struct pointers")
+		}
+
+		if v.IsNil() {
+			return
+		}
+
+		ao.visited = make(map[configVisit]bool)
+
+		ao.visitConfigFields(v.Elem(), func(_ *AppOptions, typeVal reflect.StructField, fieldVal reflect.Value, key, _ string) {
+
+			if isProtoMessageType(typeVal.Type) {
+				return
+			}
+
+			if fromTags {
+
+				if defVal := typeVal.Tag.Get("default"); len(defVal) > 0 {
+					src.configs[key] = NewConfig(defVal)
+				}
+
+				return
+
+			}
+
+			if fieldVal.IsNil() {
+				return
+			}
+
+			if cfg := fieldVal.Interface().(Config); cfg.IsSet() {
+				src.configs[key] = cfg
+			}
+
+		})
+
+	}
+
+	for _, keySet := range keySets {
+		collect(keySet, true)
+	}
+
+	if ao.Defaults != nil {
+		collect(ao.Defaults, false)
+	}
+
+	ao.getState().defaults = src
+
+	return src
+
+}
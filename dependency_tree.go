@@ -0,0 +1,96 @@
+package app
+
+import (
+	"reflect"
+	"strings"
+)
+
+// dependencyNode is a dependency discovered by InjectAny, named after the
+// struct field holding it.
+type dependencyNode struct {
+	name     string
+	path     string
+	logName  string
+	value    any
+	children []*dependencyNode
+}
+
+// newDependencyNode names the logger of the dependency after its path, unless
+// the field has a logger tag replacing its segment.
+func newDependencyNode(parent *dependencyNode, name, logSegment string, value any) *dependencyNode {
+
+	if len(logSegment) == 0 {
+		logSegment = name
+	}
+
+	node := &dependencyNode{
+		name:    name,
+		path:    name,
+		logName: logSegment,
+		value:   value,
+	}
+
+	if parent != nil {
+		node.path = strings.Join([]string{parent.path, name}, ".")
+		node.logName = strings.Join([]string{parent.logName, logSegment}, ".")
+		parent.children = append(parent.children, node)
+	}
+
+	return node
+
+}
+
+// flatten lists the tree depth-first, children before their parents, which
+// is the order dependencies are started in.
+func (n *dependencyNode) flatten() []*dependencyNode {
+
+	nodes := []*dependencyNode{}
+
+	for _, child := range n.children {
+		nodes = append(nodes, child.flatten()...)
+	}
+
+	return append(nodes, n)
+
+}
+
+type dependencyInfo struct {
+	Name     string            `json:"name"`
+	Path     string            `json:"path"`
+	Logger   string            `json:"logger"`
+	Type     string            `json:"type"`
+	Children []*dependencyInfo `json:"children,omitempty"`
+}
+
+func (n *dependencyNode) info() *dependencyInfo {
+
+	info := &dependencyInfo{
+		Name:   n.name,
+		Path:   n.path,
+		Logger: n.logName,
+		Type:   reflect.TypeOf(n.value).String(),
+	}
+
+	for _, child := range n.children {
+		info.Children = append(info.Children, child.info())
+	}
+
+	return info
+
+}
+
+func dependencyTypeName(t reflect.Type) string {
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	name := t.Name()
+
+	if sep := strings.Index(name, "["); sep >= 0 {
+		name = name[:sep]
+	}
+
+	return name
+
+}
@@ -0,0 +1,109 @@
+package app_test
+
+import (
+	"testing"
+
+	app "github.com/protomesh/go-app"
+	"github.com/protomesh/go-app/apptest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type namedRoot struct {
+	*app.Injector[*namedRoot]
+
+	PetStore *namedPetStore
+}
+
+type namedPetStore struct {
+	*app.Injector[*namedRoot]
+
+	Veterinary *namedVeterinary `logger:"vet"`
+}
+
+type namedVeterinary struct {
+	*app.Injector[*namedPetStore]
+}
+
+func TestInjectorNamedLoggers(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-log-json", "-log-dev=false", "-log-level", "info"}
+
+	deps := &namedRoot{}
+
+	a, logged := captureStderr(t, func() app.AppWithClose {
+		return app.NewApp(deps, opts)
+	})
+
+	assert.NoError(t, a.SetLogLevel("namedRoot.PetStore.vet", app.LogLevelDebug))
+
+	deps.Log().Info("from root")
+	deps.PetStore.Log().Debug("store debug")
+	deps.PetStore.Log().Info("from store")
+	deps.PetStore.Veterinary.Log().Debug("from vet")
+
+	out := logged()
+
+	assert.Regexp(t, `"logger":"namedRoot","caller":"[^"]+","msg":"from root"`, out)
+	assert.Regexp(t, `"logger":"namedRoot.PetStore","caller":"[^"]+","msg":"from store"`, out)
+	assert.Regexp(t, `"logger":"namedRoot.PetStore.vet","caller":"[^"]+","msg":"from vet"`, out)
+	assert.NotContains(t, out, "store debug")
+
+}
+
+func TestInjectorNamedLoggersLevelBySuffix(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-log-json", "-log-dev=false", "-log-level", "info"}
+
+	deps := &namedRoot{}
+
+	a, logged := captureStderr(t, func() app.AppWithClose {
+		return app.NewApp(deps, opts)
+	})
+
+	assert.NoError(t, a.SetLogLevel("vet", app.LogLevelDebug))
+
+	deps.PetStore.Log().Debug("store debug")
+	deps.PetStore.Veterinary.Log().Debug("vet debug")
+
+	// The longest match wins over the level of the pet store
+	assert.NoError(t, a.SetLogLevel("PetStore", app.LogLevelWarn))
+
+	deps.PetStore.Log().Info("store info")
+	deps.PetStore.Veterinary.Log().Debug("vet debug again")
+
+	out := logged()
+
+	assert.NotContains(t, out, "store debug")
+	assert.Contains(t, out, "vet debug")
+	assert.NotContains(t, out, "store info")
+	assert.Contains(t, out, "vet debug again")
+
+}
+
+// unnamedLogger hides the Named method of the logger it wraps.
+type unnamedLogger struct {
+	app.Logger
+}
+
+func TestInjectorLoggerWithoutNames(t *testing.T) {
+
+	log := apptest.NewLogger()
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{}
+	opts.Logger = unnamedLogger{log}
+
+	deps := &namedRoot{}
+
+	a := app.NewApp(deps, opts)
+	defer a.Close()
+
+	deps.PetStore.Veterinary.Log().Info("from vet")
+
+	log.AssertLogged(t, app.LogLevelInfo, "from vet")
+	assert.Empty(t, log.Find(app.LogLevelInfo, "from vet")[0].Logger)
+
+}
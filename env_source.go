@@ -46,6 +46,10 @@ func (e *envSource) Get(k string) Config {
 
 }
 
+func (e *envSource) Keys(prefix string) []string {
+	return childKeysOf(e.configs, prefix)
+}
+
 func (e *envSource) Has(k string) bool {
 
 	_, ok := e.configs[k]
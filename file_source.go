@@ -3,6 +3,7 @@ package app
 import (
 	"encoding/json"
 	"io/ioutil"
+	"strconv"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -78,6 +79,10 @@ func (f *fileSource) Load() error {
 
 func (f *fileSource) Get(k string) Config {
 
+	if len(k) == 0 {
+		return NewConfig(f.config.Raw)
+	}
+
 	res := f.config.Get(k)
 
 	if res.Exists() {
@@ -88,6 +93,42 @@ func (f *fileSource) Get(k string) Config {
 
 }
 
+func (f *fileSource) Keys(prefix string) []string {
+
+	children := newChildKeySet()
+
+	res := f.config
+	if len(prefix) > 0 {
+		res = f.config.Get(prefix)
+	}
+
+	switch {
+
+	case res.IsArray():
+
+		for i := range res.Array() {
+			children.add(strconv.Itoa(i))
+		}
+
+	case res.IsObject():
+
+		res.ForEach(func(key, _ gjson.Result) bool {
+			children.add(key.String())
+			return true
+		})
+
+	}
+
+	return children.sorted()
+
+}
+
 func (f *fileSource) Has(k string) bool {
+
+	if len(k) == 0 {
+		return f.config.Exists()
+	}
+
 	return f.config.Get(k).Exists()
+
 }
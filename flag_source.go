@@ -61,6 +61,10 @@ func (f *flagSource) Get(k string) Config {
 
 }
 
+func (f *flagSource) Keys(prefix string) []string {
+	return childKeysOf(f.configs, prefix)
+}
+
 func (f *flagSource) Has(k string) bool {
 
 	_, ok := f.onlySet[k]
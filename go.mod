@@ -1,6 +1,6 @@
 module github.com/protomesh/go-app
 
-go 1.20
+go 1.21
 
 require (
 	github.com/BurntSushi/toml v1.3.2
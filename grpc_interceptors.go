@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 func GrpcPathPrefixUnaryInterceptor(prefix string) grpc.UnaryClientInterceptor {
@@ -19,3 +20,47 @@ func GrpcPathPrefixStreamInterceptor(prefix string) grpc.StreamClientInterceptor
 		return streamer(ctx, desc, cc, modifiedMethod, opts...)
 	}
 }
+
+// GrpcLogContextUnaryInterceptor puts the logger in the context of the calls,
+// with the gRPC method and the values of the incoming metadata keys, like
+// x-request-id, as log fields.
+func GrpcLogContextUnaryInterceptor(logger Logger, metadataKeys ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(grpcLogContext(ctx, logger, info.FullMethod, metadataKeys), req)
+	}
+}
+
+// GrpcLogContextStreamInterceptor is the stream version of
+// GrpcLogContextUnaryInterceptor.
+func GrpcLogContextStreamInterceptor(logger Logger, metadataKeys ...string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &grpcContextStream{ss, grpcLogContext(ss.Context(), logger, info.FullMethod, metadataKeys)})
+	}
+}
+
+type grpcContextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *grpcContextStream) Context() context.Context {
+	return s.ctx
+}
+
+func grpcLogContext(ctx context.Context, logger Logger, method string, metadataKeys []string) context.Context {
+
+	kv := []interface{}{"grpc.method", method}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+
+		for _, key := range metadataKeys {
+			if vals := md.Get(key); len(vals) > 0 {
+				kv = append(kv, key, vals[0])
+			}
+		}
+
+	}
+
+	return WithLogFields(WithLogger(ctx, logger), kv...)
+
+}
@@ -0,0 +1,306 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type HealthProbe string
+
+const (
+	LivenessProbe  HealthProbe = "liveness"
+	ReadinessProbe HealthProbe = "readiness"
+)
+
+const (
+	defaultHealthCheckTimeout = 5 * time.Second
+	defaultHealthCheckCache   = time.Second
+)
+
+var (
+	HealthCheckAlreadyRegisteredError = errors.New("HealthCheckAlreadyRegistered")
+	HealthCheckNotFoundError          = errors.New("HealthCheckNotFound")
+)
+
+// HealthChecker is implemented by dependencies reporting whether they're
+// ready to serve, they're registered as readiness checks named after their
+// path in the dependency tree.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+// LivenessChecker is implemented by dependencies that can tell when the
+// process must be restarted.
+type LivenessChecker interface {
+	CheckLiveness(ctx context.Context) error
+}
+
+type HealthCheckFunc func(ctx context.Context) error
+
+type HealthCheckOptions struct {
+	// Timeout of each run of the check, defaults to 5 seconds.
+	Timeout time.Duration
+
+	// CacheTTL reuses the last result for that long, defaults to 1 second.
+	// A negative value disables caching.
+	CacheTTL time.Duration
+}
+
+type HealthCheckResult struct {
+	Name      string        `json:"name"`
+	Healthy   bool          `json:"healthy"`
+	Error     string        `json:"error,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	CheckedAt time.Time     `json:"checkedAt"`
+}
+
+type HealthReport struct {
+	Probe   HealthProbe         `json:"probe"`
+	Healthy bool                `json:"healthy"`
+	Checks  []HealthCheckResult `json:"checks"`
+}
+
+type healthCheck struct {
+	name  string
+	probe HealthProbe
+	check HealthCheckFunc
+	opts  HealthCheckOptions
+
+	mu     sync.Mutex
+	last   HealthCheckResult
+	cached bool
+}
+
+// HealthRegistry aggregates named checks into the liveness and readiness of
+// the app.
+type HealthRegistry struct {
+	mu     sync.RWMutex
+	checks map[string]*healthCheck
+	order  []string
+
+	defaults HealthCheckOptions
+}
+
+func NewHealthRegistry(defaults *HealthCheckOptions) *HealthRegistry {
+
+	h := &HealthRegistry{
+		checks: make(map[string]*healthCheck),
+		defaults: HealthCheckOptions{
+			Timeout:  defaultHealthCheckTimeout,
+			CacheTTL: defaultHealthCheckCache,
+		},
+	}
+
+	if defaults != nil {
+		h.defaults = h.withDefaults(defaults)
+	}
+
+	return h
+
+}
+
+func (h *HealthRegistry) withDefaults(opts *HealthCheckOptions) HealthCheckOptions {
+
+	merged := h.defaults
+
+	if opts == nil {
+		return merged
+	}
+
+	if opts.Timeout > 0 {
+		merged.Timeout = opts.Timeout
+	}
+
+	if opts.CacheTTL != 0 {
+		merged.CacheTTL = opts.CacheTTL
+	}
+
+	return merged
+
+}
+
+// Register adds a named check to the probe, the options default to the ones
+// of the registry when nil.
+func (h *HealthRegistry) Register(name string, probe HealthProbe, check HealthCheckFunc, opts *HealthCheckOptions) error {
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.checks[name]; ok {
+		return fmt.Errorf("%w: %s", HealthCheckAlreadyRegisteredError, name)
+	}
+
+	h.checks[name] = &healthCheck{
+		name:  name,
+		probe: probe,
+		check: check,
+		opts:  h.withDefaults(opts),
+	}
+
+	h.order = append(h.order, name)
+
+	return nil
+
+}
+
+// Check runs the checks of the probe concurrently, the probe is healthy when
+// all of them are.
+func (h *HealthRegistry) Check(ctx context.Context, probe HealthProbe) HealthReport {
+
+	h.mu.RLock()
+
+	checks := []*healthCheck{}
+
+	for _, name := range h.order {
+		if check := h.checks[name]; check.probe == probe {
+			checks = append(checks, check)
+		}
+	}
+
+	h.mu.RUnlock()
+
+	report := HealthReport{
+		Probe:   probe,
+		Healthy: true,
+		Checks:  make([]HealthCheckResult, len(checks)),
+	}
+
+	wg := &sync.WaitGroup{}
+
+	for i, check := range checks {
+
+		wg.Add(1)
+
+		go func(i int, check *healthCheck) {
+			defer wg.Done()
+			report.Checks[i] = check.run(ctx)
+		}(i, check)
+
+	}
+
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		report.Healthy = report.Healthy && result.Healthy
+	}
+
+	return report
+
+}
+
+// CheckOne runs a single check by name.
+func (h *HealthRegistry) CheckOne(ctx context.Context, name string) (HealthCheckResult, error) {
+
+	h.mu.RLock()
+	check, ok := h.checks[name]
+	h.mu.RUnlock()
+
+	if !ok {
+		return HealthCheckResult{}, fmt.Errorf("%w: %s", HealthCheckNotFoundError, name)
+	}
+
+	return check.run(ctx), nil
+
+}
+
+func (c *healthCheck) run(ctx context.Context) HealthCheckResult {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cached && c.opts.CacheTTL > 0 && time.Since(c.last.CheckedAt) < c.opts.CacheTTL {
+		return c.last
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	errCh := make(chan error, 1)
+
+	go func() {
+
+		defer func() {
+			if r := recover(); r != nil {
+				errCh <- fmt.Errorf("health check panicked: %v", r)
+			}
+		}()
+
+		errCh <- c.check(ctx)
+
+	}()
+
+	var err error
+
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	c.last = HealthCheckResult{
+		Name:      c.name,
+		Healthy:   err == nil,
+		Duration:  time.Since(start),
+		CheckedAt: start,
+	}
+
+	if err != nil {
+		c.last.Error = err.Error()
+	}
+
+	c.cached = true
+
+	return c.last
+
+}
+
+// Handler serves the report of the probe as JSON, with status 200 when
+// healthy or 503 otherwise.
+func (h *HealthRegistry) Handler(probe HealthProbe) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		report := h.Check(r.Context(), probe)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if !report.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		json.NewEncoder(w).Encode(report)
+
+	})
+
+}
+
+// registerDependencyChecks registers the dependencies of the tree
+// implementing HealthChecker or LivenessChecker.
+func (h *HealthRegistry) registerDependencyChecks(tree *dependencyNode) error {
+
+	for _, node := range tree.flatten() {
+
+		if checker, ok := node.value.(HealthChecker); ok {
+			if err := h.Register(node.path, ReadinessProbe, checker.CheckHealth, nil); err != nil {
+				return err
+			}
+		}
+
+		if checker, ok := node.value.(LivenessChecker); ok {
+			if err := h.Register(node.path+".liveness", LivenessProbe, checker.CheckLiveness, nil); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	return nil
+
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const defaultHealthWatchInterval = 5 * time.Second
+
+type grpcHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+
+	registry *HealthRegistry
+	interval time.Duration
+}
+
+// GrpcHealthServer implements grpc.health.v1.Health over the registry. The
+// empty service name reports the readiness of the app, "liveness" its
+// liveness and any other name the check registered with that name.
+func (h *HealthRegistry) GrpcHealthServer() grpc_health_v1.HealthServer {
+	return &grpcHealthServer{registry: h, interval: defaultHealthWatchInterval}
+}
+
+func (s *grpcHealthServer) status(ctx context.Context, service string) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+
+	healthy := false
+
+	switch service {
+
+	case "", string(ReadinessProbe):
+		healthy = s.registry.Check(ctx, ReadinessProbe).Healthy
+
+	case string(LivenessProbe):
+		healthy = s.registry.Check(ctx, LivenessProbe).Healthy
+
+	default:
+
+		result, err := s.registry.CheckOne(ctx, service)
+		if err != nil {
+			return grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN, err
+		}
+
+		healthy = result.Healthy
+
+	}
+
+	if healthy {
+		return grpc_health_v1.HealthCheckResponse_SERVING, nil
+	}
+
+	return grpc_health_v1.HealthCheckResponse_NOT_SERVING, nil
+
+}
+
+func (s *grpcHealthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+
+	servingStatus, err := s.status(ctx, req.GetService())
+	if errors.Is(err, HealthCheckNotFoundError) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{Status: servingStatus}, nil
+
+}
+
+// Watch sends the status of the service when it changes, checking it on an
+// interval.
+func (s *grpcHealthServer) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+
+	ctx := stream.Context()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	last := grpc_health_v1.HealthCheckResponse_UNKNOWN
+	first := true
+
+	for {
+
+		servingStatus, _ := s.status(ctx, req.GetService())
+
+		if first || servingStatus != last {
+
+			if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: servingStatus}); err != nil {
+				return err
+			}
+
+			first = false
+			last = servingStatus
+
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+
+	}
+
+}
@@ -0,0 +1,123 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	app "github.com/protomesh/go-app"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthRegistryChecks(t *testing.T) {
+
+	h := app.NewHealthRegistry(&app.HealthCheckOptions{Timeout: 20 * time.Millisecond, CacheTTL: -1})
+
+	calls := atomic.Int32{}
+
+	assert.NoError(t, h.Register("db", app.ReadinessProbe, func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	}, &app.HealthCheckOptions{CacheTTL: time.Hour}))
+
+	assert.NoError(t, h.Register("slow", app.ReadinessProbe, func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}, nil))
+
+	assert.NoError(t, h.Register("loop", app.LivenessProbe, func(ctx context.Context) error {
+		return nil
+	}, nil))
+
+	err := h.Register("db", app.LivenessProbe, func(ctx context.Context) error { return nil }, nil)
+	assert.ErrorIs(t, err, app.HealthCheckAlreadyRegisteredError)
+
+	report := h.Check(context.Background(), app.ReadinessProbe)
+	assert.False(t, report.Healthy)
+	assert.Len(t, report.Checks, 2)
+	assert.True(t, report.Checks[0].Healthy)
+	assert.Equal(t, "slow", report.Checks[1].Name)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[1].Error)
+
+	h.Check(context.Background(), app.ReadinessProbe)
+	assert.EqualValues(t, 1, calls.Load())
+
+	assert.True(t, h.Check(context.Background(), app.LivenessProbe).Healthy)
+
+	rec := httptest.NewRecorder()
+	h.Handler(app.ReadinessProbe).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.Handler(app.LivenessProbe).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"loop"`)
+
+	srv := h.GrpcHealthServer()
+
+	res, err := srv.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, res.Status)
+
+	res, err = srv.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "db"})
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, res.Status)
+
+	_, err = srv.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "unknown"})
+	assert.Error(t, err)
+
+}
+
+type checkedRoot struct {
+	*app.Injector[*checkedRoot]
+
+	Store *checkedStore
+}
+
+type checkedStore struct {
+	*app.Injector[*checkedRoot]
+
+	down bool
+}
+
+func (s *checkedStore) CheckHealth(ctx context.Context) error {
+
+	if s.down {
+		return errors.New("store is down")
+	}
+
+	return nil
+
+}
+
+func TestAppHealthReadiness(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-health-cache-ttl", "0"}
+
+	deps := &checkedRoot{}
+
+	a := app.NewApp(deps, opts)
+	defer a.Close()
+
+	report := a.Health().Check(context.Background(), app.ReadinessProbe)
+	assert.False(t, report.Healthy)
+	assert.Equal(t, "app", report.Checks[0].Name)
+	assert.Equal(t, "checkedRoot.Store", report.Checks[1].Name)
+
+	assert.NoError(t, a.Start(context.Background()))
+	assert.True(t, a.Health().Check(context.Background(), app.ReadinessProbe).Healthy)
+
+	deps.Store.down = true
+	assert.False(t, a.Health().Check(context.Background(), app.ReadinessProbe).Healthy)
+	assert.Same(t, a.Health(), deps.Store.Health())
+
+	assert.NoError(t, a.Stop(context.Background()))
+
+}
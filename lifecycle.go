@@ -0,0 +1,149 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultStopTimeout = 30 * time.Second
+
+var AppAlreadyStartedError = errors.New("AppAlreadyStarted")
+
+// Starter is implemented by dependencies that need to run something, like
+// opening connections or listening on a port, before the app is ready.
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper is implemented by dependencies that release resources when the app
+// shuts down.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+var AppNotStartedError = errors.New("AppNotStarted")
+
+type lifecycle struct {
+	mu      sync.Mutex
+	tree    *dependencyNode
+	running []*dependencyNode
+	started bool
+
+	// ready is set once every dependency is started, until stopping.
+	ready atomic.Bool
+}
+
+// start calls Start on the dependencies depth-first, children before their
+// parents. When one of them fails the ones already started are stopped.
+func (l *lifecycle) start(ctx context.Context) error {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.started {
+		return AppAlreadyStartedError
+	}
+
+	l.started = true
+
+	if l.tree == nil {
+		return nil
+	}
+
+	for _, node := range l.tree.flatten() {
+
+		if starter, ok := node.value.(Starter); ok {
+
+			if err := starter.Start(ctx); err != nil {
+
+				startErr := fmt.Errorf("failed to start %s: %w", node.path, err)
+
+				stopCtx, cancel := stopContext(context.Background(), defaultStopTimeout)
+				defer cancel()
+
+				return errors.Join(startErr, l.stopRunning(stopCtx))
+
+			}
+
+		}
+
+		l.running = append(l.running, node)
+
+	}
+
+	l.ready.Store(true)
+
+	return nil
+
+}
+
+// checkReady is the readiness check of the app itself.
+func (l *lifecycle) checkReady(ctx context.Context) error {
+
+	if !l.ready.Load() {
+		return AppNotStartedError
+	}
+
+	return nil
+
+}
+
+// stop calls Stop on the started dependencies in the reverse order they were
+// started, giving up on the remaining ones once the context is done.
+func (l *lifecycle) stop(ctx context.Context, timeout time.Duration) error {
+
+	l.ready.Store(false)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ctx, cancel := stopContext(ctx, timeout)
+	defer cancel()
+
+	return l.stopRunning(ctx)
+
+}
+
+func (l *lifecycle) stopRunning(ctx context.Context) error {
+
+	errs := []error{}
+
+	for i := len(l.running) - 1; i >= 0; i-- {
+
+		node := l.running[i]
+
+		stopper, ok := node.value.(Stopper)
+		if !ok {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", node.path, err))
+			continue
+		}
+
+		if err := stopper.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", node.path, err))
+		}
+
+	}
+
+	l.running = nil
+
+	return errors.Join(errs...)
+
+}
+
+func stopContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+
+}
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+type loggerContextKey struct{}
+
+type logFieldsContextKey struct{}
+
+type LogContextFieldFunc func(ctx context.Context) (interface{}, bool)
+
+type logContextField struct {
+	key     string
+	extract LogContextFieldFunc
+}
+
+var (
+	logContextFieldsMu sync.RWMutex
+	logContextFields   []logContextField
+)
+
+// RegisterLogContextField adds a key-value to the messages logged with the
+// *Ctx methods whenever the function finds a value in the context, like a
+// trace ID set by a tracing library.
+func RegisterLogContextField(key string, extract LogContextFieldFunc) {
+
+	logContextFieldsMu.Lock()
+	defer logContextFieldsMu.Unlock()
+
+	logContextFields = append(logContextFields, logContextField{key, extract})
+
+}
+
+// WithLogFields returns a context carrying key-values added to the messages
+// logged with the *Ctx methods.
+func WithLogFields(ctx context.Context, kv ...interface{}) context.Context {
+
+	fields, _ := ctx.Value(logFieldsContextKey{}).([]interface{})
+
+	return context.WithValue(ctx, logFieldsContextKey{}, append(fields[:len(fields):len(fields)], kv...))
+
+}
+
+// WithLogger returns a context carrying the logger, retrieved by LoggerFrom.
+func WithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// LoggerFrom returns the logger of the context, or a logger discarding
+// everything when there's none.
+func LoggerFrom(ctx context.Context) Logger {
+
+	if logger, ok := ctx.Value(loggerContextKey{}).(Logger); ok {
+		return logger
+	}
+
+	return newStdLogger(zap.NewNop(), nil, "")
+
+}
+
+// contextLogFields returns the key-values of the context followed by the
+// registered fields found in it.
+func contextLogFields(ctx context.Context, kv []interface{}) []interface{} {
+
+	if ctx == nil {
+		return kv
+	}
+
+	fields, _ := ctx.Value(logFieldsContextKey{}).([]interface{})
+
+	logContextFieldsMu.RLock()
+	registered := logContextFields
+	logContextFieldsMu.RUnlock()
+
+	if len(fields) == 0 && len(registered) == 0 {
+		return kv
+	}
+
+	all := make([]interface{}, 0, len(fields)+2*len(registered)+len(kv))
+	all = append(all, fields...)
+
+	for _, field := range registered {
+		if val, ok := field.extract(ctx); ok {
+			all = append(all, field.key, val)
+		}
+	}
+
+	return append(all, kv...)
+
+}
+
+func (s *stdLogger) DebugCtx(ctx context.Context, message string, kv ...interface{}) {
+
+	kv = contextLogFields(ctx, kv)
+
+	if fields, ok := typedFields(kv); ok {
+		s.desugared.Debug(message, fields...)
+		return
+	}
+
+	s.logger.Debugw(message, kv...)
+
+}
+
+func (s *stdLogger) InfoCtx(ctx context.Context, message string, kv ...interface{}) {
+
+	kv = contextLogFields(ctx, kv)
+
+	if fields, ok := typedFields(kv); ok {
+		s.desugared.Info(message, fields...)
+		return
+	}
+
+	s.logger.Infow(message, kv...)
+
+}
+
+func (s *stdLogger) WarnCtx(ctx context.Context, message string, kv ...interface{}) {
+
+	kv = contextLogFields(ctx, kv)
+
+	if fields, ok := typedFields(kv); ok {
+		s.desugared.Warn(message, fields...)
+		return
+	}
+
+	s.logger.Warnw(message, kv...)
+
+}
+
+func (s *stdLogger) ErrorCtx(ctx context.Context, message string, kv ...interface{}) {
+
+	kv = contextLogFields(ctx, kv)
+
+	if fields, ok := typedFields(kv); ok {
+		s.desugared.Error(message, fields...)
+		return
+	}
+
+	s.logger.Errorw(message, kv...)
+
+}
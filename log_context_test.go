@@ -0,0 +1,58 @@
+package app_test
+
+import (
+	"context"
+	"testing"
+
+	app "github.com/protomesh/go-app"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type traceIdKey struct{}
+
+func init() {
+	app.RegisterLogContextField("trace.id", func(ctx context.Context) (interface{}, bool) {
+		id, ok := ctx.Value(traceIdKey{}).(string)
+		return id, ok
+	})
+}
+
+func TestLoggerContextFields(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-log-json", "-log-dev=false"}
+
+	a, logged := captureStderr(t, func() app.AppWithClose {
+		return app.NewApp(&adminRoot{}, opts)
+	})
+
+	ctx := app.WithLogger(context.Background(), a.Log())
+	ctx = app.WithLogFields(ctx, "tenant", "acme")
+	ctx = context.WithValue(ctx, traceIdKey{}, "abc123")
+
+	app.LoggerFrom(ctx).InfoCtx(ctx, "order placed", "order", 42)
+	a.Log().Info("no context")
+
+	interceptor := app.GrpcLogContextUnaryInterceptor(a.Log(), "x-request-id")
+
+	grpcCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-1"))
+
+	_, err := interceptor(grpcCtx, nil, &grpc.UnaryServerInfo{FullMethod: "/pets.Store/Get"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		app.LoggerFrom(ctx).WarnCtx(ctx, "pet not found")
+		return nil, nil
+	})
+	assert.NoError(t, err)
+
+	app.LoggerFrom(context.Background()).ErrorCtx(context.Background(), "discarded")
+
+	out := logged()
+
+	assert.Regexp(t, `"msg":"order placed","tenant":"acme","trace.id":"abc123","order":42`, out)
+	assert.Regexp(t, `"msg":"no context"}`, out)
+	assert.Regexp(t, `"msg":"pet not found","grpc.method":"/pets.Store/Get","x-request-id":"req-1"`, out)
+	assert.NotContains(t, out, "discarded")
+
+}
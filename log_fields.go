@@ -0,0 +1,76 @@
+package app
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Field is a typed key-value, passed among the key-values of the Logger
+// methods. When all the key-values are fields, the loggers of the app skip the
+// key-value handling of the sugared logger.
+type Field = zap.Field
+
+func String(key string, val string) Field {
+	return zap.String(key, val)
+}
+
+func Int(key string, val int) Field {
+	return zap.Int(key, val)
+}
+
+func Int64(key string, val int64) Field {
+	return zap.Int64(key, val)
+}
+
+func Float64(key string, val float64) Field {
+	return zap.Float64(key, val)
+}
+
+func Bool(key string, val bool) Field {
+	return zap.Bool(key, val)
+}
+
+func Duration(key string, val time.Duration) Field {
+	return zap.Duration(key, val)
+}
+
+func Time(key string, val time.Time) Field {
+	return zap.Time(key, val)
+}
+
+// Err logs the error under the "error" key, nothing when it's nil.
+func Err(err error) Field {
+	return zap.Error(err)
+}
+
+// Object logs a value encoding itself, see zapcore.ObjectMarshaler.
+func Object(key string, val zapcore.ObjectMarshaler) Field {
+	return zap.Object(key, val)
+}
+
+// Any picks the field constructor from the type of the value, falling back
+// to reflection.
+func Any(key string, val interface{}) Field {
+	return zap.Any(key, val)
+}
+
+// typedFields returns the key-values as fields when they're all fields.
+func typedFields(kv []interface{}) ([]Field, bool) {
+
+	for _, val := range kv {
+		if _, ok := val.(Field); !ok {
+			return nil, false
+		}
+	}
+
+	fields := make([]Field, len(kv))
+
+	for i, val := range kv {
+		fields[i] = val.(Field)
+	}
+
+	return fields, true
+
+}
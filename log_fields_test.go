@@ -0,0 +1,90 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	app "github.com/protomesh/go-app"
+	"github.com/protomesh/go-app/apptest"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogFields(t *testing.T) {
+
+	log := apptest.NewLogger()
+
+	log.With(app.String("service", "pets")).Error("Failed to fetch pets",
+		app.Err(errors.New("timeout")),
+		app.Int("attempt", 3),
+		app.Duration("elapsed", 2*time.Second),
+		app.Bool("retry", true),
+		"store", "main",
+	)
+
+	log.AssertLogged(t, app.LogLevelError, "Failed to fetch pets",
+		"service", "pets",
+		"error", "timeout",
+		"attempt", 3,
+		"elapsed", 2*time.Second,
+		"retry", true,
+		"store", "main",
+	)
+
+}
+
+func TestLogFieldsCaller(t *testing.T) {
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	log := app.NewZapLogger(zap.New(core, zap.AddCaller()))
+
+	log.Info("Fields only", app.String("pet", "rex"), app.Int("age", 3))
+	log.With(app.String("service", "pets")).Warn("Fields only with fields")
+	log.InfoCtx(context.Background(), "Fields only with a context", app.Bool("retry", true))
+	log.Info("Mixed", app.String("pet", "rex"), "age", 3)
+
+	entries := logs.All()
+
+	if assert.Len(t, entries, 4) {
+
+		for _, entry := range entries {
+			assert.Equal(t, "log_fields_test.go", filepath.Base(entry.Caller.File), entry.Message)
+		}
+
+		assert.Equal(t, map[string]interface{}{"pet": "rex", "age": int64(3)}, entries[0].ContextMap())
+		assert.Equal(t, map[string]interface{}{"service": "pets"}, entries[1].ContextMap())
+		assert.Equal(t, map[string]interface{}{"pet": "rex", "age": int64(3)}, entries[3].ContextMap())
+
+	}
+
+}
+
+func BenchmarkLogFields(b *testing.B) {
+
+	encoder := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+
+	log := app.NewZapLogger(zap.New(zapcore.NewCore(encoder, zapcore.AddSync(io.Discard), zapcore.DebugLevel)))
+
+	b.Run("fields", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			log.Info("Fetched pets", app.String("store", "main"), app.Int("count", 3))
+		}
+	})
+
+	b.Run("key-values", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			log.Info("Fetched pets", "store", "main", "count", 3)
+		}
+	})
+
+}
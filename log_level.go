@@ -0,0 +1,312 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type LogLevel string
+
+// LogLevelsUnsupportedError is returned when changing the levels of a logger
+// not built by the app, see AppOptions.Logger and AppOptions.LoggerFactory.
+var LogLevelsUnsupportedError = errors.New("LogLevelsUnsupported")
+
+const (
+	LogLevelDebug  LogLevel = "debug"
+	LogLevelInfo   LogLevel = "info"
+	LogLevelWarn   LogLevel = "warn"
+	LogLevelError  LogLevel = "error"
+	LogLevelDPanic LogLevel = "dpanic"
+	LogLevelPanic  LogLevel = "panic"
+	LogLevelFatal  LogLevel = "fatal"
+)
+
+func (l LogLevel) zapLevel() (zapcore.Level, error) {
+
+	level, err := zapcore.ParseLevel(strings.ToLower(string(l)))
+	if err != nil {
+		return level, fmt.Errorf("invalid log level '%s'", l)
+	}
+
+	return level, nil
+
+}
+
+// logLevels holds the level of the app logger and the overrides of the named
+// loggers, matched by name segments, so "db" also applies to "db.pool" and
+// to "root.db".
+type logLevels struct {
+	mu        sync.RWMutex
+	global    zapcore.Level
+	overrides map[string]zapcore.Level
+
+	// min is the lowest of all levels, checked before looking up the name.
+	min atomic.Int32
+}
+
+func newLogLevels(level zapcore.Level) *logLevels {
+
+	l := &logLevels{
+		global:    level,
+		overrides: make(map[string]zapcore.Level),
+	}
+
+	l.min.Store(int32(level))
+
+	return l
+
+}
+
+func (l *logLevels) updateMin() {
+
+	min := l.global
+
+	for _, level := range l.overrides {
+		if level < min {
+			min = level
+		}
+	}
+
+	l.min.Store(int32(min))
+
+}
+
+func (l *logLevels) set(name string, level zapcore.Level) {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(name) == 0 {
+		l.global = level
+	} else {
+		l.overrides[name] = level
+	}
+
+	l.updateMin()
+
+}
+
+func (l *logLevels) reset(name string) {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.overrides, name)
+
+	l.updateMin()
+
+}
+
+// Enabled implements zapcore.LevelEnabler for the cores, which are only
+// reached once enabledFor accepted the entry.
+func (l *logLevels) Enabled(level zapcore.Level) bool {
+	return int32(level) >= l.min.Load()
+}
+
+// levelOf returns the level of the longest prefix of the name ending with the
+// name of an override, so "vet" applies to "root.PetStore.vet" and to
+// "root.PetStore.vet.db".
+func (l *logLevels) levelOf(name string) zapcore.Level {
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if len(l.overrides) == 0 {
+		return l.global
+	}
+
+	for prefix := name; len(prefix) > 0; {
+
+		for suffix := prefix; ; {
+
+			if level, ok := l.overrides[suffix]; ok {
+				return level
+			}
+
+			sep := strings.Index(suffix, ".")
+			if sep < 0 {
+				break
+			}
+
+			suffix = suffix[sep+1:]
+
+		}
+
+		sep := strings.LastIndex(prefix, ".")
+		if sep < 0 {
+			break
+		}
+
+		prefix = prefix[:sep]
+
+	}
+
+	return l.global
+
+}
+
+func (l *logLevels) enabledFor(name string, level zapcore.Level) bool {
+
+	if !l.Enabled(level) {
+		return false
+	}
+
+	return level >= l.levelOf(name)
+
+}
+
+func (l *logLevels) snapshot() logLevelsState {
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	state := logLevelsState{Level: LogLevel(l.global.String())}
+
+	if len(l.overrides) > 0 {
+
+		state.Overrides = make(map[string]LogLevel, len(l.overrides))
+
+		for name, level := range l.overrides {
+			state.Overrides[name] = LogLevel(level.String())
+		}
+
+	}
+
+	return state
+
+}
+
+type logLevelsState struct {
+	Level     LogLevel            `json:"level"`
+	Overrides map[string]LogLevel `json:"overrides,omitempty"`
+}
+
+type logLevelRequest struct {
+	Name  string   `json:"name"`
+	Level LogLevel `json:"level"`
+}
+
+// leveledCore drops the entries below the level of their logger name.
+type leveledCore struct {
+	zapcore.Core
+	levels *logLevels
+}
+
+func (c *leveledCore) Enabled(level zapcore.Level) bool {
+	return c.levels.Enabled(level)
+}
+
+func (c *leveledCore) With(fields []zapcore.Field) zapcore.Core {
+	return &leveledCore{c.Core.With(fields), c.levels}
+}
+
+func (c *leveledCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+
+	if !c.levels.enabledFor(ent.LoggerName, ent.Level) {
+		return ce
+	}
+
+	return c.Core.Check(ent, ce)
+
+}
+
+func (a *app) SetLogLevel(name string, level LogLevel) error {
+
+	if a.logLevels == nil {
+		return fmt.Errorf("%w: can't set the level of a custom logger", LogLevelsUnsupportedError)
+	}
+
+	zapLevel, err := level.zapLevel()
+	if err != nil {
+		return err
+	}
+
+	a.logLevels.set(name, zapLevel)
+
+	return nil
+
+}
+
+func (a *app) ResetLogLevel(name string) error {
+
+	if a.logLevels == nil {
+		return fmt.Errorf("%w: can't reset the level of a custom logger", LogLevelsUnsupportedError)
+	}
+
+	a.logLevels.reset(name)
+
+	return nil
+
+}
+
+func (a *app) LogLevels() (LogLevel, map[string]LogLevel) {
+
+	if a.logLevels == nil {
+		return "", map[string]LogLevel{}
+	}
+
+	state := a.logLevels.snapshot()
+
+	return state.Level, state.Overrides
+
+}
+
+// LogLevelHandler serves the log levels as JSON. GET returns them, PUT
+// {"level":"info"} sets the level of the app and PUT {"name":"db",
+// "level":"debug"} the level of the loggers named db or ending with .db, see
+// SetLogLevel, which DELETE ?name=db removes. It responds 501 with a custom
+// logger.
+func (a *app) LogLevelHandler() http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if a.logLevels == nil {
+			http.Error(w, LogLevelsUnsupportedError.Error(), http.StatusNotImplemented)
+			return
+		}
+
+		switch r.Method {
+
+		case http.MethodGet:
+
+		case http.MethodPut, http.MethodPost:
+
+			req := &logLevelRequest{}
+
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if err := a.SetLogLevel(req.Name, req.Level); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+		case http.MethodDelete:
+
+			if err := a.ResetLogLevel(r.URL.Query().Get("name")); err != nil {
+				http.Error(w, err.Error(), http.StatusNotImplemented)
+				return
+			}
+
+		default:
+
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+
+		}
+
+		writeAdminJson(w, a.logLevels.snapshot())
+
+	})
+
+}
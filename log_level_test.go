@@ -0,0 +1,191 @@
+package app_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	app "github.com/protomesh/go-app"
+	"github.com/protomesh/go-app/apptest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// captureStderr returns the app created while the standard error is
+// redirected, along with a function returning what was logged.
+func captureStderr(t *testing.T, newApp func() app.AppWithClose) (app.AppWithClose, func() string) {
+
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+
+	stderr := os.Stderr
+	os.Stderr = w
+	a := newApp()
+	os.Stderr = stderr
+
+	out := &bytes.Buffer{}
+	done := make(chan struct{})
+
+	go func() {
+		io.Copy(out, r)
+		close(done)
+	}()
+
+	return a, func() string {
+		a.Close()
+		w.Close()
+		<-done
+		return out.String()
+	}
+
+}
+
+func TestAppSetLogLevel(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-log-json", "-log-dev=false", "-log-level", "info"}
+
+	a, logged := captureStderr(t, func() app.AppWithClose {
+		return app.NewApp(&adminRoot{}, opts)
+	})
+
+	assert.NoError(t, a.SetLogLevel("db", app.LogLevelDebug))
+	assert.Error(t, a.SetLogLevel("db", "verbose"))
+
+	a.Log().Debug("root debug")
+	app.NameLogger(a.Log(), "db").Debug("db debug")
+	app.NameLogger(app.NameLogger(a.Log(), "db"), "pool").Debug("pool debug")
+	app.NameLogger(a.Log(), "http").Debug("http debug")
+	app.NameLogger(a.Log(), "http").Info("http info")
+
+	assert.NoError(t, a.SetLogLevel("", app.LogLevelError))
+	a.ResetLogLevel("db")
+	app.NameLogger(a.Log(), "db").Info("db info")
+
+	level, overrides := a.LogLevels()
+	assert.Equal(t, app.LogLevelError, level)
+	assert.Empty(t, overrides)
+
+	out := logged()
+
+	assert.NotContains(t, out, "root debug")
+	assert.Contains(t, out, "db debug")
+	assert.Contains(t, out, "pool debug")
+	assert.NotContains(t, out, "http debug")
+	assert.Contains(t, out, "http info")
+	assert.NotContains(t, out, "db info")
+
+}
+
+func TestAppLogLevelHandler(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{}
+
+	a := app.NewApp(&adminRoot{}, opts)
+	defer a.Close()
+
+	handler := a.LogLevelHandler()
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := serve(http.MethodPut, "/loglevel", `{"name":"db","level":"warn"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"debug","overrides":{"db":"warn"}}`, rec.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPut, "/loglevel", `{"level":"loud"}`).Code)
+
+	rec = serve(http.MethodDelete, "/loglevel?name=db", "")
+	assert.JSONEq(t, `{"level":"debug"}`, rec.Body.String())
+
+}
+
+func TestAppLogLevelsWithCustomLogger(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{}
+	opts.Logger = apptest.NewLogger()
+
+	a := app.NewApp(&adminRoot{}, opts)
+	defer a.Close()
+
+	assert.ErrorIs(t, a.SetLogLevel("db", app.LogLevelWarn), app.LogLevelsUnsupportedError)
+	assert.ErrorIs(t, a.ResetLogLevel("db"), app.LogLevelsUnsupportedError)
+
+	level, overrides := a.LogLevels()
+	assert.Empty(t, level)
+	assert.Empty(t, overrides)
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		rec := httptest.NewRecorder()
+		a.LogLevelHandler().ServeHTTP(rec, httptest.NewRequest(method, "/loglevel", strings.NewReader(`{"level":"warn"}`)))
+		assert.Equal(t, http.StatusNotImplemented, rec.Code)
+	}
+
+}
+
+func TestAppLogLevelParsing(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-log-level", "WARN"}
+
+	a := app.NewApp(&adminRoot{}, opts)
+	defer a.Close()
+
+	level, _ := a.LogLevels()
+	assert.Equal(t, app.LogLevelWarn, level)
+
+	assert.False(t, a.Log().Enabled(app.LogLevelInfo))
+	assert.True(t, a.Log().Enabled(app.LogLevelWarn))
+	assert.True(t, a.Log().Enabled(app.LogLevelFatal))
+	assert.False(t, a.Log().Enabled("loud"))
+
+	assert.NoError(t, a.SetLogLevel("db", app.LogLevelDebug))
+	assert.True(t, app.NameLogger(a.Log(), "db").Enabled(app.LogLevelDebug))
+	assert.False(t, app.NameLogger(a.Log(), "http").Enabled(app.LogLevelDebug))
+
+	assert.Panics(t, func() { a.Log().DPanic("development panic") })
+
+	invalid, _ := newTestOptions(t)
+	invalid.Args = []string{"-log-level", "verbose"}
+
+	assert.PanicsWithError(t, "invalid value for 'log.level': invalid log level 'verbose'", func() {
+		app.NewApp(&adminRoot{}, invalid)
+	})
+
+}
+
+func TestRuntimeLogLevelSurvivesReload(t *testing.T) {
+
+	filePath := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(filePath, []byte("log:\n  level: info\n"), 0644))
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-config-file", filePath}
+
+	a := app.NewApp(&adminRoot{}, opts)
+
+	assert.NoError(t, a.SetLogLevel("", app.LogLevelDebug))
+
+	// Reload is what SIGHUP runs
+	assert.NoError(t, a.Reload())
+
+	level, _ := a.LogLevels()
+	assert.Equal(t, app.LogLevelDebug, level)
+
+	assert.NoError(t, os.WriteFile(filePath, []byte("log:\n  level: warn\n"), 0644))
+	assert.NoError(t, a.Reload())
+
+	level, _ = a.LogLevels()
+	assert.Equal(t, app.LogLevelWarn, level)
+
+}
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var UnknownLogOutputError = errors.New("UnknownLogOutput")
+
+// logOutput is an element of log.outputs, writing the entries between its
+// levels with its own encoding.
+type logOutput struct {
+	Type     Config `config:"type,str" default:"stderr" usage:"Log output (stdout, stderr, file, syslog)"`
+	Path     Config `config:"path,str" usage:"Log file path, or syslog address"`
+	Network  Config `config:"network,str" usage:"Syslog network (unix, unixgram, udp, tcp), the local syslog when empty"`
+	Tag      Config `config:"tag,str" usage:"Syslog tag, defaults to the program name"`
+	Encoding Config `config:"encoding,str" usage:"Log encoding (console, json), defaults to log.json"`
+	Level    Config `config:"level,str" usage:"Lowest level written to the output"`
+	MaxLevel Config `config:"max.level,str" usage:"Highest level written to the output"`
+
+	MaxSize        Config `config:"max.size,int" default:"100" usage:"Size in megabytes of the log file before it's rotated, 0 disables the rotation by size"`
+	RotateInterval Config `config:"rotate.interval,duration" usage:"Time after which the log file is rotated, like 24h for daily files at midnight UTC, 0 only rotates by size"`
+	MaxAge         Config `config:"max.age,duration" usage:"Age of the rotated log files before they're deleted, 0 keeps them"`
+	MaxBackups     Config `config:"max.backups,int" usage:"Number of rotated log files kept, 0 keeps all of them"`
+}
+
+type logOutputCore struct {
+	core  zapcore.Core
+	close func()
+}
+
+func (o *logOutput) levels(levels *logLevels) (zapcore.LevelEnabler, error) {
+
+	minLevel := zapcore.DebugLevel
+	maxLevel := zapcore.FatalLevel
+
+	if o.Level.IsSet() {
+
+		level, err := LogLevel(o.Level.StringVal()).zapLevel()
+		if err != nil {
+			return nil, err
+		}
+
+		minLevel = level
+
+	}
+
+	if o.MaxLevel.IsSet() {
+
+		level, err := LogLevel(o.MaxLevel.StringVal()).zapLevel()
+		if err != nil {
+			return nil, err
+		}
+
+		maxLevel = level
+
+	}
+
+	return zap.LevelEnablerFunc(func(level zapcore.Level) bool {
+		return level >= minLevel && level <= maxLevel && levels.Enabled(level)
+	}), nil
+
+}
+
+func (o *logOutput) encoder(zapConfig zap.Config, colors bool) (zapcore.Encoder, error) {
+
+	encoding := zapConfig.Encoding
+	if o.Encoding.IsSet() {
+		encoding = strings.ToLower(o.Encoding.StringVal())
+	}
+
+	encoderConfig := zapConfig.EncoderConfig
+	if !colors {
+		encoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
+	}
+
+	switch encoding {
+	case "json":
+		return zapcore.NewJSONEncoder(encoderConfig), nil
+	case "console":
+		return zapcore.NewConsoleEncoder(encoderConfig), nil
+	}
+
+	return nil, fmt.Errorf("invalid log encoding '%s'", encoding)
+
+}
+
+func (o *logOutput) open(zapConfig zap.Config, levels *logLevels) (*logOutputCore, error) {
+
+	enabler, err := o.levels(levels)
+	if err != nil {
+		return nil, err
+	}
+
+	outputType := strings.ToLower(o.Type.StringVal())
+
+	encoder, err := o.encoder(zapConfig, outputType == "stdout" || outputType == "stderr")
+	if err != nil {
+		return nil, err
+	}
+
+	switch outputType {
+
+	case "stdout", "stderr":
+
+		sink, closeSink, err := zap.Open(outputType)
+		if err != nil {
+			return nil, err
+		}
+
+		return &logOutputCore{zapcore.NewCore(encoder, sink, enabler), closeSink}, nil
+
+	case "file":
+
+		if !o.Path.IsSet() {
+			return nil, errors.New("log file output without path")
+		}
+
+		file, err := openRotatingFile(o.Path.StringVal(), o.MaxSize.Int64Val()*1024*1024, o.RotateInterval.DurationVal(), o.MaxAge.DurationVal(), int(o.MaxBackups.Int64Val()))
+		if err != nil {
+			return nil, err
+		}
+
+		return &logOutputCore{zapcore.NewCore(encoder, file, enabler), func() { file.Close() }}, nil
+
+	case "syslog":
+
+		return openSyslogCore(o.Network.StringVal(), o.Path.StringVal(), o.Tag.StringVal(), encoder, enabler)
+
+	}
+
+	return nil, fmt.Errorf("%w: %s", UnknownLogOutputError, outputType)
+
+}
+
+// openOutputs opens log.outputs, or the default output when none is
+// configured, returning their cores combined.
+func (l *loggerBuilder[D]) openOutputs(zapConfig zap.Config) (zapcore.Core, func(), error) {
+
+	if len(l.Outputs) == 0 {
+
+		sink, closeSink, err := zap.Open(zapConfig.OutputPaths...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		encoder := zapcore.NewConsoleEncoder(zapConfig.EncoderConfig)
+		if zapConfig.Encoding == "json" {
+			encoder = zapcore.NewJSONEncoder(zapConfig.EncoderConfig)
+		}
+
+		return zapcore.NewCore(encoder, sink, l.levels), closeSink, nil
+
+	}
+
+	cores := []zapcore.Core{}
+	closers := []func(){}
+
+	closeAll := func() {
+		for _, closeOutput := range closers {
+			closeOutput()
+		}
+	}
+
+	for i, output := range l.Outputs {
+
+		if output == nil {
+			continue
+		}
+
+		opened, err := output.open(zapConfig, l.levels)
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("invalid log output %d: %w", i, err)
+		}
+
+		cores = append(cores, opened.core)
+		closers = append(closers, opened.close)
+
+	}
+
+	return zapcore.NewTee(cores...), closeAll, nil
+
+}
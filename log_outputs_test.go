@@ -0,0 +1,246 @@
+package app_test
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	app "github.com/protomesh/go-app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newAppWithConfigFile(t *testing.T, content string) app.AppWithClose {
+
+	filePath := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-config-file", filePath}
+
+	return app.NewApp(&adminRoot{}, opts)
+
+}
+
+func TestLogOutputsSplitByLevel(t *testing.T) {
+
+	dir := t.TempDir()
+
+	a := newAppWithConfigFile(t, `
+log:
+  dev: false
+  outputs:
+    - type: file
+      path: `+filepath.Join(dir, "app.log")+`
+      encoding: json
+      max:
+        level: warn
+    - type: file
+      path: `+filepath.Join(dir, "errors.log")+`
+      level: error
+`)
+
+	a.Log().Info("started")
+	a.Log().Error("failed")
+	a.Close()
+
+	appLog, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(appLog), `"msg":"started"`)
+	assert.NotContains(t, string(appLog), "failed")
+
+	errorsLog, err := os.ReadFile(filepath.Join(dir, "errors.log"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(errorsLog), "started")
+	assert.Regexp(t, `error\s+\S+\s+failed`, string(errorsLog))
+
+}
+
+func TestLogOutputsFileRotation(t *testing.T) {
+
+	dir := t.TempDir()
+
+	a := newAppWithConfigFile(t, `
+log:
+  outputs:
+    - type: file
+      path: `+filepath.Join(dir, "app.log")+`
+      max:
+        size: 1
+        backups: 1
+`)
+	defer a.Close()
+
+	chunk := strings.Repeat("x", 400*1024)
+
+	for i := 0; i < 8; i++ {
+		a.Log().Info(chunk)
+	}
+
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	assert.NoError(t, err)
+	assert.Len(t, backups, 1)
+
+	info, err := os.Stat(filepath.Join(dir, "app.log"))
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, info.Size(), int64(1024*1024))
+
+}
+
+func TestLogOutputsFileRotationKeepsEveryBackup(t *testing.T) {
+
+	dir := t.TempDir()
+
+	a := newAppWithConfigFile(t, `
+log:
+  outputs:
+    - type: file
+      path: `+filepath.Join(dir, "app.log")+`
+      max:
+        size: 1
+`)
+	defer a.Close()
+
+	// Every entry rotates the file, several in the same millisecond
+	chunk := strings.Repeat("x", 1024*1024)
+
+	for i := 0; i < 10; i++ {
+		a.Log().Info(chunk)
+	}
+
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	assert.NoError(t, err)
+	assert.Len(t, backups, 9)
+
+}
+
+func TestLogOutputsFileRotationFailure(t *testing.T) {
+
+	if os.Geteuid() == 0 {
+		t.Skip("root can rename in a read-only directory")
+	}
+
+	dir := t.TempDir()
+
+	a := newAppWithConfigFile(t, `
+log:
+  outputs:
+    - type: file
+      path: `+filepath.Join(dir, "app.log")+`
+      max:
+        size: 1
+`)
+	defer a.Close()
+
+	chunk := strings.Repeat("x", 600*1024)
+
+	a.Log().Info(chunk)
+
+	assert.NoError(t, os.Chmod(dir, 0o555))
+	defer os.Chmod(dir, 0o755)
+
+	a.Log().Info(chunk)
+	a.Log().Info("after the failed rotation")
+
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	assert.NoError(t, err)
+	assert.Empty(t, backups)
+
+	current, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(current), "after the failed rotation")
+
+}
+
+func TestLogOutputsFileRotationInterval(t *testing.T) {
+
+	dir := t.TempDir()
+
+	a := newAppWithConfigFile(t, `
+log:
+  outputs:
+    - type: file
+      path: `+filepath.Join(dir, "app.log")+`
+      rotate:
+        interval: 200ms
+`)
+	defer a.Close()
+
+	// Right after the start of the next interval
+	nextInterval := func() {
+		time.Sleep(time.Until(time.Now().Truncate(200 * time.Millisecond).Add(210 * time.Millisecond)))
+	}
+
+	nextInterval()
+
+	a.Log().Info("first")
+	a.Log().Info("same interval")
+
+	nextInterval()
+
+	a.Log().Info("next interval")
+
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	assert.NoError(t, err)
+
+	if assert.Len(t, backups, 1) {
+
+		rotated, err := os.ReadFile(backups[0])
+		assert.NoError(t, err)
+		assert.NotContains(t, string(rotated), "next interval")
+
+	}
+
+	current, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(current), "next interval")
+	assert.NotContains(t, string(current), "first")
+
+}
+
+func TestLogOutputsSyslog(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("syslog is not supported on windows")
+	}
+
+	sock := filepath.Join(t.TempDir(), "syslog.sock")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sock, Net: "unixgram"})
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	a := newAppWithConfigFile(t, `
+log:
+  dev: false
+  outputs:
+    - type: syslog
+      network: unixgram
+      path: `+sock+`
+      tag: petstore
+      encoding: json
+`)
+	defer a.Close()
+
+	a.Log().Warn("disk almost full")
+
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	assert.NoError(t, err)
+
+	// Priority 12 is user facility with warning severity
+	assert.Regexp(t, `^<12>.*petstore\[\d+\]: .*"msg":"disk almost full"`, string(buf[:n]))
+
+}
+
+func TestLogOutputsInvalid(t *testing.T) {
+
+	assert.PanicsWithError(t, "invalid log output 0: UnknownLogOutput: kafka", func() {
+		newAppWithConfigFile(t, "log:\n  outputs:\n    - type: kafka\n")
+	})
+
+}
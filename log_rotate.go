@@ -0,0 +1,308 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	rotatedLogTimeFormat = "2006-01-02T15-04-05.000"
+
+	// rotateRetryDelay is the delay before rotating again after a failure,
+	// the entries being written to the current file meanwhile
+	rotateRetryDelay = time.Minute
+)
+
+// rotatingFile is a log file renamed with the time of rotation once it gets
+// over maxSize or once an interval starts, deleting the rotated files past
+// maxAge or maxBackups. The intervals are aligned on the zero time, so a day
+// starts at midnight UTC.
+type rotatingFile struct {
+	mu sync.Mutex
+
+	path       string
+	maxSize    int64
+	interval   time.Duration
+	maxAge     time.Duration
+	maxBackups int
+
+	// file is nil when it couldn't be opened again after a rotation
+	file *os.File
+	size int64
+
+	retryAt time.Time
+
+	// window is the start of the interval of the entries in the file
+	window time.Time
+}
+
+func openRotatingFile(path string, maxSize int64, interval, maxAge time.Duration, maxBackups int) (*rotatingFile, error) {
+
+	f := &rotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		interval:   interval,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+
+}
+
+func (f *rotatingFile) open() error {
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+
+	// The last entry of an existing file tells its interval
+	if f.interval > 0 && f.size > 0 {
+		f.window = info.ModTime().Truncate(f.interval)
+	}
+
+	return nil
+
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	now := time.Now()
+
+	rotate := f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize
+
+	if f.interval > 0 && f.size > 0 && now.Truncate(f.interval).After(f.window) {
+		rotate = true
+	}
+
+	var rotateErr error
+
+	if rotate && !now.Before(f.retryAt) {
+
+		if rotateErr = f.rotate(); rotateErr != nil {
+
+			f.retryAt = now.Add(rotateRetryDelay)
+
+			if f.file == nil {
+				return 0, rotateErr
+			}
+
+		}
+
+	}
+
+	if f.interval > 0 && f.size == 0 {
+		f.window = now.Truncate(f.interval)
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	if err == nil {
+		err = rotateErr
+	}
+
+	return n, err
+
+}
+
+func (f *rotatingFile) Sync() error {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+
+	return f.file.Sync()
+
+}
+
+func (f *rotatingFile) Close() error {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+
+	return f.file.Close()
+
+}
+
+func (f *rotatingFile) backupPrefix() (string, string) {
+
+	ext := filepath.Ext(f.path)
+
+	return strings.TrimSuffix(f.path, ext) + "-", ext
+
+}
+
+// backupPath returns a free path for the rotated file, numbered when a file
+// was already rotated in the same millisecond.
+func (f *rotatingFile) backupPath() string {
+
+	prefix, ext := f.backupPrefix()
+
+	stamp := time.Now().UTC().Format(rotatedLogTimeFormat)
+
+	path := prefix + stamp + ext
+
+	for seq := 1; ; seq++ {
+
+		if _, err := os.Lstat(path); err != nil {
+			return path
+		}
+
+		path = prefix + stamp + "-" + strconv.Itoa(seq) + ext
+
+	}
+
+}
+
+// rotate renames the file and opens a new one at its path, the file is opened
+// again at its path when the rename fails so the logging goes on.
+func (f *rotatingFile) rotate() error {
+
+	err := f.file.Close()
+	f.file = nil
+
+	if err == nil {
+		err = os.Rename(f.path, f.backupPath())
+	}
+
+	if openErr := f.open(); openErr != nil {
+		return errors.Join(err, openErr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	f.cleanup()
+
+	return nil
+
+}
+
+// cleanup deletes the rotated files over the retention, ignoring errors since
+// they must not stop the logging.
+func (f *rotatingFile) cleanup() {
+
+	if f.maxAge <= 0 && f.maxBackups <= 0 {
+		return
+	}
+
+	prefix, ext := f.backupPrefix()
+
+	matches, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		return
+	}
+
+	backups := []rotatedLogFile{}
+
+	for _, match := range matches {
+
+		if backup, ok := parseRotatedLogFile(match, prefix, ext); ok {
+			backups = append(backups, backup)
+		}
+
+	}
+
+	// Newest first
+	sort.Slice(backups, func(i, j int) bool {
+
+		if !backups[i].stamp.Equal(backups[j].stamp) {
+			return backups[i].stamp.After(backups[j].stamp)
+		}
+
+		return backups[i].seq > backups[j].seq
+
+	})
+
+	for i, backup := range backups {
+
+		expired := f.maxAge > 0 && time.Since(backup.stamp) > f.maxAge
+		extra := f.maxBackups > 0 && i >= f.maxBackups
+
+		if expired || extra {
+			os.Remove(backup.path)
+		}
+
+	}
+
+}
+
+type rotatedLogFile struct {
+	path  string
+	stamp time.Time
+	seq   int
+}
+
+// parseRotatedLogFile reads the time of rotation and the number of a rotated
+// file, like app-2006-01-02T15-04-05.000-1.log.
+func parseRotatedLogFile(path, prefix, ext string) (rotatedLogFile, bool) {
+
+	name := strings.TrimSuffix(strings.TrimPrefix(path, prefix), ext)
+
+	if len(name) < len(rotatedLogTimeFormat) {
+		return rotatedLogFile{}, false
+	}
+
+	stamp, err := time.Parse(rotatedLogTimeFormat, name[:len(rotatedLogTimeFormat)])
+	if err != nil {
+		return rotatedLogFile{}, false
+	}
+
+	backup := rotatedLogFile{path: path, stamp: stamp}
+
+	if rest := name[len(rotatedLogTimeFormat):]; len(rest) > 0 {
+
+		seq, err := strconv.Atoi(strings.TrimPrefix(rest, "-"))
+		if err != nil || rest[0] != '-' || seq < 1 {
+			return rotatedLogFile{}, false
+		}
+
+		backup.seq = seq
+
+	}
+
+	return backup, true
+
+}
@@ -0,0 +1,250 @@
+package app
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const maxRateLimitedMessages = 1024
+
+// samplingConfig overrides the zap sampling with the log.sampling keys set to
+// positive values, sampling is only enabled in development mode when one of
+// them is.
+func (l *loggerBuilder[D]) samplingConfig(zapConfig zap.Config) (*zap.SamplingConfig, time.Duration) {
+
+	initial := l.LogSamplingInitial.Int64Val()
+	thereafter := l.LogSamplingThereafter.Int64Val()
+	tick := l.LogSamplingTick.DurationVal()
+
+	if initial <= 0 && thereafter <= 0 && tick <= 0 {
+		return zapConfig.Sampling, time.Second
+	}
+
+	sampling := &zap.SamplingConfig{Initial: 100, Thereafter: 100}
+
+	if zapConfig.Sampling != nil {
+		sampling.Initial = zapConfig.Sampling.Initial
+		sampling.Thereafter = zapConfig.Sampling.Thereafter
+	}
+
+	if initial > 0 {
+		sampling.Initial = int(initial)
+	}
+
+	if thereafter > 0 {
+		sampling.Thereafter = int(thereafter)
+	}
+
+	if tick <= 0 {
+		tick = time.Second
+	}
+
+	return sampling, tick
+
+}
+
+// deferredCheckCore checks the entries with the wrapped core once they're
+// written, so the sampler only counts the entries actually logged.
+type deferredCheckCore struct {
+	zapcore.Core
+}
+
+func (c *deferredCheckCore) With(fields []zapcore.Field) zapcore.Core {
+	return &deferredCheckCore{c.Core.With(fields)}
+}
+
+func (c *deferredCheckCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+
+	if c.Core.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+
+}
+
+func (c *deferredCheckCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+
+	if checked := c.Core.Check(ent, nil); checked != nil {
+		checked.Write(fields...)
+	}
+
+	return nil
+
+}
+
+type rateLimitKey struct {
+	level   zapcore.Level
+	message string
+}
+
+type rateLimitWindow struct {
+	start      time.Time
+	count      int
+	suppressed int
+}
+
+type rateLimitState struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    int
+	windows  map[rateLimitKey]*rateLimitWindow
+}
+
+// rateLimitCore lets through burst warnings or errors with the same message
+// per interval. The next one let through after the interval, or Sync, tells
+// how many were suppressed.
+type rateLimitCore struct {
+	zapcore.Core
+	state *rateLimitState
+}
+
+func newRateLimitCore(core zapcore.Core, interval time.Duration, burst int) zapcore.Core {
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimitCore{
+		Core: core,
+		state: &rateLimitState{
+			interval: interval,
+			burst:    burst,
+			windows:  make(map[rateLimitKey]*rateLimitWindow),
+		},
+	}
+
+}
+
+func suppressedMessage(message string, suppressed int) string {
+	return fmt.Sprintf("%s (%d more suppressed)", message, suppressed)
+}
+
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateLimitCore{c.Core.With(fields), c.state}
+}
+
+// Check lets Write count the entry, since the callers of Check may not write
+// it, like a logger.Check guard.
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+
+	if ent.Level < zapcore.WarnLevel || ent.Level >= zapcore.DPanicLevel {
+		return c.Core.Check(ent, ce)
+	}
+
+	if c.Core.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+
+}
+
+func (c *rateLimitCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+
+	if !c.state.allow(&ent) {
+		return nil
+	}
+
+	// The outputs only write the levels they check
+	if checked := c.Core.Check(ent, nil); checked != nil {
+		checked.Write(fields...)
+	}
+
+	return nil
+
+}
+
+// allow counts the entry in its window, telling the suppressed ones in the
+// message of the first entry of the next window.
+func (s *rateLimitState) allow(ent *zapcore.Entry) bool {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := rateLimitKey{ent.Level, ent.Message}
+
+	window, ok := s.windows[key]
+
+	if !ok || ent.Time.Sub(window.start) >= s.interval {
+
+		suppressed := 0
+		if ok {
+			suppressed = window.suppressed
+		}
+
+		if !ok && len(s.windows) >= maxRateLimitedMessages {
+			s.prune(ent.Time)
+		}
+
+		window = &rateLimitWindow{start: ent.Time}
+		s.windows[key] = window
+
+		if suppressed > 0 {
+			ent.Message = suppressedMessage(ent.Message, suppressed)
+		}
+
+	}
+
+	window.count++
+
+	if window.count > s.burst {
+		window.suppressed++
+		return false
+	}
+
+	return true
+
+}
+
+// prune drops the windows over the interval without suppressed entries.
+func (s *rateLimitState) prune(now time.Time) {
+
+	for key, window := range s.windows {
+		if window.suppressed == 0 && now.Sub(window.start) >= s.interval {
+			delete(s.windows, key)
+		}
+	}
+
+}
+
+// Sync writes the summaries of the suppressed entries before syncing.
+func (c *rateLimitCore) Sync() error {
+
+	s := c.state
+
+	s.mu.Lock()
+
+	summaries := []zapcore.Entry{}
+
+	for key, window := range s.windows {
+
+		if window.suppressed == 0 {
+			continue
+		}
+
+		summaries = append(summaries, zapcore.Entry{
+			Level:   key.level,
+			Time:    time.Now(),
+			Message: suppressedMessage(key.message, window.suppressed),
+		})
+
+		window.suppressed = 0
+
+	}
+
+	s.mu.Unlock()
+
+	for _, ent := range summaries {
+		if ce := c.Core.Check(ent, nil); ce != nil {
+			ce.Write()
+		}
+	}
+
+	return c.Core.Sync()
+
+}
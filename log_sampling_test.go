@@ -0,0 +1,87 @@
+package app_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	app "github.com/protomesh/go-app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogSampling(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-log-json", "-log-sampling-initial", "2", "-log-sampling-thereafter", "5", "-log-sampling-tick", "1h"}
+
+	a, logged := captureStderr(t, func() app.AppWithClose {
+		return app.NewApp(&adminRoot{}, opts)
+	})
+
+	for i := 0; i < 12; i++ {
+		a.Log().Info("hot loop")
+	}
+
+	// The first 2, then every 5th: the 7th and the 12th
+	assert.Equal(t, 4, strings.Count(logged(), "hot loop"))
+
+}
+
+func TestLogRateLimit(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-log-json", "-log-dev=false", "-log-rate-limit-interval", "50ms", "-log-rate-limit-burst", "2"}
+
+	a, logged := captureStderr(t, func() app.AppWithClose {
+		return app.NewApp(&adminRoot{}, opts)
+	})
+
+	for i := 0; i < 5; i++ {
+		a.Log().Error("connection refused")
+		a.Log().Info("not limited")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	a.Log().Error("connection refused")
+
+	for i := 0; i < 4; i++ {
+		a.Log().Warn("slow query")
+	}
+
+	out := logged()
+
+	assert.Equal(t, 5, strings.Count(out, "not limited"))
+	// The entry after the interval carries the summary
+	assert.Equal(t, 3, strings.Count(out, "connection refused"))
+	assert.Contains(t, out, `"msg":"connection refused (3 more suppressed)"`)
+	assert.Contains(t, out, `"msg":"slow query (2 more suppressed)"`)
+
+}
+
+func TestLogEnabledDoesNotCountEntries(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-log-json", "-log-dev=false", "-log-level", "info", "-log-rate-limit-interval", "1m"}
+
+	a, logged := captureStderr(t, func() app.AppWithClose {
+		return app.NewApp(&adminRoot{}, opts)
+	})
+
+	// Production mode samples after 100 entries per second
+	for i := 0; i < 300; i++ {
+		assert.True(t, a.Log().Enabled(app.LogLevelInfo))
+	}
+
+	for i := 0; i < 10; i++ {
+		assert.True(t, a.Log().Enabled(app.LogLevelWarn))
+	}
+
+	assert.False(t, a.Log().Enabled(app.LogLevelDebug))
+
+	a.Log().Warn("after probing")
+
+	assert.Contains(t, logged(), `"msg":"after probing"`)
+
+}
@@ -0,0 +1,336 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// The slog levels of the zap levels slog doesn't have.
+const (
+	slogLevelDPanic = slog.LevelError + 2
+	slogLevelPanic  = slog.LevelError + 4
+	slogLevelFatal  = slog.LevelError + 8
+)
+
+func (l LogLevel) slogLevel() slog.Level {
+
+	switch l {
+	case LogLevelDebug:
+		return slog.LevelDebug
+	case LogLevelInfo:
+		return slog.LevelInfo
+	case LogLevelWarn:
+		return slog.LevelWarn
+	case LogLevelDPanic:
+		return slogLevelDPanic
+	case LogLevelPanic:
+		return slogLevelPanic
+	case LogLevelFatal:
+		return slogLevelFatal
+	}
+
+	return slog.LevelError
+
+}
+
+// slogHandler is a slog.Handler writing the records through a Logger.
+type slogHandler struct {
+	logger Logger
+	group  string
+}
+
+// NewSlogHandler returns a slog.Handler writing through the logger, so the
+// libraries logging with slog share the logging pipeline of the app. Groups
+// prefix the keys of their attributes, like "request.id".
+func NewSlogHandler(logger Logger) slog.Handler {
+	return &slogHandler{logger: logger}
+}
+
+// recordLogger is implemented by the loggers able to write an entry with the
+// time and the caller of a slog record.
+type recordLogger interface {
+	logRecord(ctx context.Context, level LogLevel, t time.Time, pc uintptr, message string, kv []interface{})
+}
+
+func (s *stdLogger) logRecord(ctx context.Context, level LogLevel, t time.Time, pc uintptr, message string, kv []interface{}) {
+
+	zapLevel, err := level.zapLevel()
+	if err != nil {
+		return
+	}
+
+	logger := s.logger.With(contextLogFields(ctx, kv)...).Desugar()
+
+	ce := logger.Check(zapLevel, message)
+	if ce == nil {
+		return
+	}
+
+	if !t.IsZero() {
+		ce.Time = t
+	}
+
+	// The caller found by zap is the slog handler
+	ce.Caller = zapcore.EntryCaller{}
+
+	if pc != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		ce.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, true)
+		ce.Caller.Function = frame.Function
+	}
+
+	ce.Write()
+
+}
+
+// slogLogLevel maps the slog levels to the levels up to error, the ones above
+// it don't panic nor exit.
+func slogLogLevel(level slog.Level) LogLevel {
+
+	switch {
+	case level < slog.LevelInfo:
+		return LogLevelDebug
+	case level < slog.LevelWarn:
+		return LogLevelInfo
+	case level < slog.LevelError:
+		return LogLevelWarn
+	}
+
+	return LogLevelError
+
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.logger.Enabled(slogLogLevel(level))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+
+	kv := make([]interface{}, 0, 2*record.NumAttrs())
+
+	record.Attrs(func(attr slog.Attr) bool {
+		kv = appendSlogAttr(kv, h.group, attr)
+		return true
+	})
+
+	level := slogLogLevel(record.Level)
+
+	// The loggers of the app write the caller and the time of the record
+	if entryLogger, ok := h.logger.(recordLogger); ok {
+		entryLogger.logRecord(ctx, level, record.Time, record.PC, record.Message, kv)
+		return nil
+	}
+
+	switch level {
+	case LogLevelDebug:
+		h.logger.DebugCtx(ctx, record.Message, kv...)
+	case LogLevelInfo:
+		h.logger.InfoCtx(ctx, record.Message, kv...)
+	case LogLevelWarn:
+		h.logger.WarnCtx(ctx, record.Message, kv...)
+	default:
+		h.logger.ErrorCtx(ctx, record.Message, kv...)
+	}
+
+	return nil
+
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+
+	kv := make([]interface{}, 0, 2*len(attrs))
+
+	for _, attr := range attrs {
+		kv = appendSlogAttr(kv, h.group, attr)
+	}
+
+	return &slogHandler{logger: h.logger.With(kv...), group: h.group}
+
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{logger: h.logger, group: joinConfigKey(h.group, name)}
+}
+
+// appendSlogAttr appends the attribute as key-values, flattening the groups.
+func appendSlogAttr(kv []interface{}, group string, attr slog.Attr) []interface{} {
+
+	if attr.Equal(slog.Attr{}) {
+		return kv
+	}
+
+	val := attr.Value.Resolve()
+
+	if val.Kind() == slog.KindGroup {
+
+		// Attributes of a group without key belong to the enclosing group
+		if len(attr.Key) > 0 {
+			group = joinConfigKey(group, attr.Key)
+		}
+
+		for _, groupAttr := range val.Group() {
+			kv = appendSlogAttr(kv, group, groupAttr)
+		}
+
+		return kv
+
+	}
+
+	return append(kv, joinConfigKey(group, attr.Key), val.Any())
+
+}
+
+// slogLogger is a Logger writing through a slog.Handler.
+type slogLogger struct {
+	handler slog.Handler
+	name    string
+}
+
+// NewSlogLogger returns a Logger writing through the slog handler, for apps
+// logging with slog, see AppOptions.LoggerFactory. Named loggers are logged
+// with a "logger" attribute, and Fatal exits after logging.
+func NewSlogLogger(handler slog.Handler) Logger {
+	return &slogLogger{handler: handler}
+}
+
+func (s *slogLogger) log(ctx context.Context, level LogLevel, message string, kv []interface{}) {
+
+	slogLevel := level.slogLevel()
+
+	if !s.handler.Enabled(ctx, slogLevel) {
+		return
+	}
+
+	// Skip runtime.Callers, log and the Logger method
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+
+	record := slog.NewRecord(time.Now(), slogLevel, message, pcs[0])
+
+	if len(s.name) > 0 {
+		record.AddAttrs(slog.String("logger", s.name))
+	}
+
+	record.AddAttrs(slogAttrs(kv)...)
+
+	s.handler.Handle(ctx, record)
+
+}
+
+// slogAttrs turns the key-values into attributes, the fields included.
+func slogAttrs(kv []interface{}) []slog.Attr {
+
+	attrs := make([]slog.Attr, 0, len(kv)/2)
+
+	for i := 0; i < len(kv); i++ {
+
+		switch typedVal := kv[i].(type) {
+
+		case Field:
+
+			enc := zapcore.NewMapObjectEncoder()
+			typedVal.AddTo(enc)
+
+			for key, val := range enc.Fields {
+				attrs = append(attrs, slog.Any(key, val))
+			}
+
+		case slog.Attr:
+
+			attrs = append(attrs, typedVal)
+
+		case string:
+
+			if i+1 == len(kv) {
+				attrs = append(attrs, slog.String("!BADKEY", typedVal))
+				break
+			}
+
+			attrs = append(attrs, slog.Any(typedVal, kv[i+1]))
+			i++
+
+		default:
+
+			attrs = append(attrs, slog.Any("!BADKEY", typedVal))
+
+		}
+
+	}
+
+	return attrs
+
+}
+
+func (s *slogLogger) Debug(message string, kv ...interface{}) {
+	s.log(context.Background(), LogLevelDebug, message, kv)
+}
+
+func (s *slogLogger) Info(message string, kv ...interface{}) {
+	s.log(context.Background(), LogLevelInfo, message, kv)
+}
+
+func (s *slogLogger) Warn(message string, kv ...interface{}) {
+	s.log(context.Background(), LogLevelWarn, message, kv)
+}
+
+func (s *slogLogger) Error(message string, kv ...interface{}) {
+	s.log(context.Background(), LogLevelError, message, kv)
+}
+
+func (s *slogLogger) DPanic(message string, kv ...interface{}) {
+	s.log(context.Background(), LogLevelDPanic, message, kv)
+}
+
+func (s *slogLogger) Panic(message string, kv ...interface{}) {
+	s.log(context.Background(), LogLevelPanic, message, kv)
+	panic(message)
+}
+
+func (s *slogLogger) Fatal(message string, kv ...interface{}) {
+	s.log(context.Background(), LogLevelFatal, message, kv)
+	os.Exit(1)
+}
+
+func (s *slogLogger) Enabled(level LogLevel) bool {
+	return s.handler.Enabled(context.Background(), level.slogLevel())
+}
+
+func (s *slogLogger) DebugCtx(ctx context.Context, message string, kv ...interface{}) {
+	s.log(ctx, LogLevelDebug, message, contextLogFields(ctx, kv))
+}
+
+func (s *slogLogger) InfoCtx(ctx context.Context, message string, kv ...interface{}) {
+	s.log(ctx, LogLevelInfo, message, contextLogFields(ctx, kv))
+}
+
+func (s *slogLogger) WarnCtx(ctx context.Context, message string, kv ...interface{}) {
+	s.log(ctx, LogLevelWarn, message, contextLogFields(ctx, kv))
+}
+
+func (s *slogLogger) ErrorCtx(ctx context.Context, message string, kv ...interface{}) {
+	s.log(ctx, LogLevelError, message, contextLogFields(ctx, kv))
+}
+
+func (s *slogLogger) With(kv ...interface{}) Logger {
+	return &slogLogger{handler: s.handler.WithAttrs(slogAttrs(kv)), name: s.name}
+}
+
+func (s *slogLogger) Named(name string) Logger {
+
+	if len(name) == 0 {
+		return s
+	}
+
+	if len(s.name) > 0 {
+		name = strings.Join([]string{s.name, name}, ".")
+	}
+
+	return &slogLogger{handler: s.handler, name: name}
+
+}
@@ -0,0 +1,101 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	app "github.com/protomesh/go-app"
+	"github.com/protomesh/go-app/apptest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogHandlerWritesThroughLogger(t *testing.T) {
+
+	log := apptest.NewLogger()
+
+	logger := slog.New(app.NewSlogHandler(log.Named("lib"))).With("service", "pets").WithGroup("request")
+
+	logger.Info("Handled", "id", 7, slog.Group("user", "name", "ann"))
+	logger.Error("Failed", "error", errors.New("timeout"))
+
+	log.AssertLogged(t, app.LogLevelInfo, "Handled", "service", "pets", "request.id", 7, "request.user.name", "ann")
+	log.AssertLogged(t, app.LogLevelError, "Failed", "request.error", "timeout")
+
+	assert.Equal(t, "lib", log.Entries()[0].Logger)
+
+}
+
+func TestSlogLoggerWritesThroughHandler(t *testing.T) {
+
+	out := &bytes.Buffer{}
+
+	log := app.NewSlogLogger(slog.NewJSONHandler(out, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	app.NameLogger(app.NameLogger(log, "db"), "pool").With(app.String("table", "pets")).Warn("Slow query", "ms", 250, app.Err(errors.New("timeout")))
+	log.Debug("Dropped")
+
+	assert.False(t, log.Enabled(app.LogLevelDebug))
+	assert.True(t, log.Enabled(app.LogLevelFatal))
+
+	assert.Regexp(t, `"level":"WARN","msg":"Slow query","table":"pets","logger":"db.pool","ms":250,"error":"timeout"`, out.String())
+	assert.NotContains(t, out.String(), "Dropped")
+
+}
+
+func TestAppLoggerFactory(t *testing.T) {
+
+	out := &bytes.Buffer{}
+
+	var logConfig app.LoggerConfig
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-log-level", "warn", "-log-json"}
+	opts.LoggerFactory = func(config app.LoggerConfig) (app.Logger, error) {
+		logConfig = config
+		return app.NewSlogLogger(slog.NewJSONHandler(out, nil)), nil
+	}
+
+	deps := &namedRoot{}
+
+	app.NewApp(deps, opts)
+
+	deps.PetStore.Log().Info("From store")
+
+	assert.Equal(t, app.LoggerConfig{Level: app.LogLevelWarn, Json: true, Dev: true}, logConfig)
+	assert.Regexp(t, `"msg":"From store","logger":"namedRoot.PetStore"`, out.String())
+
+}
+
+func TestSlogHandlerKeepsCallerAndTime(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{"-log-json", "-log-dev=false"}
+
+	a, logged := captureStderr(t, func() app.AppWithClose {
+		return app.NewApp(&adminRoot{}, opts)
+	})
+
+	logger := slog.New(app.NewSlogHandler(app.NameLogger(a.Log(), "lib")))
+
+	_, file, line, _ := runtime.Caller(0)
+	logger.Info("From slog", "pet", "rex")
+
+	record := slog.NewRecord(time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC), slog.LevelWarn, "Recorded", 0)
+	assert.NoError(t, app.NewSlogHandler(a.Log()).Handle(context.Background(), record))
+
+	out := logged()
+
+	caller := fmt.Sprintf("%s/%s:%d", filepath.Base(filepath.Dir(file)), filepath.Base(file), line+1)
+
+	assert.Contains(t, out, `"logger":"lib","caller":"`+caller+`","msg":"From slog","pet":"rex"`)
+	assert.Contains(t, out, `"ts":"2001-02-03T04:05:06Z","msg":"Recorded"`)
+
+}
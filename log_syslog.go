@@ -0,0 +1,82 @@
+//go:build !windows && !plan9
+
+package app
+
+import (
+	"log/syslog"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// syslogCore writes each entry as a syslog message with the severity of its
+// level.
+type syslogCore struct {
+	zapcore.LevelEnabler
+	enc    zapcore.Encoder
+	writer *syslog.Writer
+}
+
+func openSyslogCore(network, addr, tag string, enc zapcore.Encoder, enabler zapcore.LevelEnabler) (*logOutputCore, error) {
+
+	writer, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	core := &syslogCore{LevelEnabler: enabler, enc: enc, writer: writer}
+
+	return &logOutputCore{core, func() { writer.Close() }}, nil
+
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+
+	enc := c.enc.Clone()
+
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+
+	return &syslogCore{LevelEnabler: c.LevelEnabler, enc: enc, writer: c.writer}
+
+}
+
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+
+}
+
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+
+	defer buf.Free()
+
+	msg := buf.String()
+
+	switch {
+	case ent.Level >= zapcore.DPanicLevel:
+		return c.writer.Crit(msg)
+	case ent.Level == zapcore.ErrorLevel:
+		return c.writer.Err(msg)
+	case ent.Level == zapcore.WarnLevel:
+		return c.writer.Warning(msg)
+	case ent.Level == zapcore.InfoLevel:
+		return c.writer.Info(msg)
+	}
+
+	return c.writer.Debug(msg)
+
+}
+
+func (c *syslogCore) Sync() error {
+	return nil
+}
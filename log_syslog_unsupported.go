@@ -0,0 +1,13 @@
+//go:build windows || plan9
+
+package app
+
+import (
+	"errors"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func openSyslogCore(network, addr, tag string, enc zapcore.Encoder, enabler zapcore.LevelEnabler) (*logOutputCore, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
@@ -0,0 +1,13 @@
+// Command logcheck reports the malformed key-values passed to the app.Logger
+// methods, standalone or with go vet -vettool.
+package main
+
+import (
+	"github.com/protomesh/go-app/logcheck"
+
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(logcheck.Analyzer)
+}
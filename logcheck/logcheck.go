@@ -0,0 +1,216 @@
+// Package logcheck defines an analyzer reporting the malformed key-values
+// passed to the Logger methods, which zap only reports at runtime.
+package logcheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const (
+	appPath     = "github.com/protomesh/go-app"
+	zapcorePath = "go.uber.org/zap/zapcore"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "logcheck",
+	Doc:      "report malformed key-values passed to the app.Logger methods\n\nThe methods are checked on app.Logger and on the types implementing it. The key-values must alternate string keys and values, or be app.Field values.",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+
+		call := n.(*ast.CallExpr)
+
+		// Forwarded key-values can't be checked
+		if call.Ellipsis.IsValid() {
+			return
+		}
+
+		fn, ok := loggerMethod(pass, call)
+		if !ok {
+			return
+		}
+
+		sig := fn.Type().(*types.Signature)
+
+		if !sig.Variadic() || len(call.Args) < sig.Params().Len()-1 {
+			return
+		}
+
+		checkKeyValues(pass, fn.Name(), call.Args[sig.Params().Len()-1:])
+
+	})
+
+	return nil, nil
+
+}
+
+// loggerMethod returns the method of app.Logger called, on the interface or on
+// any type implementing it.
+func loggerMethod(pass *analysis.Pass, call *ast.CallExpr) (*types.Func, bool) {
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
+		return nil, false
+	}
+
+	fn, ok := selection.Obj().(*types.Func)
+	if !ok {
+		return nil, false
+	}
+
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return nil, false
+	}
+
+	if isNamed(recv.Type(), appPath, "Logger") {
+		return fn, true
+	}
+
+	logger := loggerInterface(pass.Pkg)
+	if logger == nil {
+		return nil, false
+	}
+
+	// The other methods of the implementations aren't checked
+	if method, _, _ := types.LookupFieldOrMethod(logger, false, nil, fn.Name()); method == nil {
+		return nil, false
+	}
+
+	recvType := selection.Recv()
+
+	return fn, types.Implements(recvType, logger) || types.Implements(types.NewPointer(recvType), logger)
+
+}
+
+// loggerInterface returns app.Logger when the package depends on it.
+func loggerInterface(pkg *types.Package) *types.Interface {
+
+	seen := make(map[*types.Package]bool)
+	pending := []*types.Package{pkg}
+
+	for len(pending) > 0 {
+
+		next := pending[0]
+		pending = pending[1:]
+
+		if seen[next] {
+			continue
+		}
+
+		seen[next] = true
+
+		if next.Path() == appPath {
+
+			obj, ok := next.Scope().Lookup("Logger").(*types.TypeName)
+			if !ok {
+				return nil
+			}
+
+			iface, _ := obj.Type().Underlying().(*types.Interface)
+
+			return iface
+
+		}
+
+		pending = append(pending, next.Imports()...)
+
+	}
+
+	return nil
+
+}
+
+func checkKeyValues(pass *analysis.Pass, method string, kv []ast.Expr) {
+
+	for i := 0; i < len(kv); i++ {
+
+		key := kv[i]
+		keyType := pass.TypesInfo.TypeOf(key)
+
+		if keyType == nil {
+			return
+		}
+
+		if isField(keyType) {
+			continue
+		}
+
+		switch {
+
+		case types.Implements(keyType, errorType):
+
+			pass.Reportf(key.Pos(), "%s: error %s passed as a key, use app.Err", method, types.ExprString(key))
+
+			return
+
+		case types.IsInterface(keyType):
+
+			// Either a key or a field, the next ones can't be checked
+			return
+
+		case !isString(keyType):
+
+			pass.Reportf(key.Pos(), "%s: key %s of type %s is not a string", method, types.ExprString(key), keyType)
+
+			return
+
+		case i+1 == len(kv):
+
+			pass.Reportf(key.Pos(), "%s: key %s has no value", method, types.ExprString(key))
+
+			return
+
+		}
+
+		i++
+
+	}
+
+}
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// isString reports whether the type is string, named string types aren't
+// keys for zap.
+func isString(t types.Type) bool {
+
+	basic, ok := t.(*types.Basic)
+
+	return ok && (basic.Kind() == types.String || basic.Kind() == types.UntypedString)
+
+}
+
+// isField reports whether the type is app.Field, an alias of zapcore.Field.
+func isField(t types.Type) bool {
+	return isNamed(t, zapcorePath, "Field")
+}
+
+func isNamed(t types.Type, path, name string) bool {
+
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Obj()
+
+	return obj.Pkg() != nil && obj.Pkg().Path() == path && obj.Name() == name
+
+}
@@ -0,0 +1,13 @@
+package logcheck_test
+
+import (
+	"testing"
+
+	"github.com/protomesh/go-app/logcheck"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), logcheck.Analyzer, "a")
+}
@@ -0,0 +1,61 @@
+package a
+
+import (
+	"errors"
+
+	app "github.com/protomesh/go-app"
+)
+
+type key string
+
+type embedding struct {
+	app.Logger
+}
+
+func logs(log app.Logger, kv []interface{}, any interface{}) {
+
+	err := errors.New("failed")
+
+	log.Info("ok", "pet", "rex", "age", 3)
+	log.Info("ok", app.String("pet", "rex"), "age", 3, app.String("owner", "ann"))
+	log.Error("ok", app.Err(err), "pet", "rex")
+	log.Info("ok", kv...)
+	log.Info("ok", any, 3, 4)
+
+	log.Info("odd", "pet", "rex", "age") // want `Info: key "age" has no value`
+	log.Error("error as key", err)       // want `Error: error err passed as a key, use app.Err`
+	log.Info("int key", 3, "three")      // want `Info: key 3 of type int is not a string`
+	log.Info("named key", key("pet"), 1) // want `Info: key key\("pet"\) of type a.key is not a string`
+
+	log.With("pet") // want `With: key "pet" has no value`
+
+	e := &embedding{log}
+	e.Info("embedded", "pet") // want `Info: key "pet" has no value`
+
+}
+
+// zapLogger implements app.Logger without embedding it.
+type zapLogger struct{}
+
+func (z zapLogger) Info(message string, kv ...interface{})  {}
+func (z zapLogger) Error(message string, kv ...interface{}) {}
+func (z *zapLogger) With(kv ...interface{}) app.Logger      { return z }
+func (z zapLogger) Trace(message string, kv ...interface{}) {}
+
+// printer has the methods of app.Logger but doesn't implement it.
+type printer struct{}
+
+func (p printer) Info(message string, kv ...interface{}) {}
+
+func implementations(z zapLogger, p printer) {
+
+	z.Info("ok", "pet", "rex")
+	z.Info("odd", "pet") // want `Info: key "pet" has no value`
+
+	zp := &zapLogger{}
+	zp.With(3, "three") // want `With: key 3 of type int is not a string`
+
+	z.Trace("not a Logger method", "pet")
+	p.Info("not a Logger", "pet")
+
+}
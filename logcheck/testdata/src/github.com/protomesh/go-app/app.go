@@ -0,0 +1,19 @@
+package app
+
+import "go.uber.org/zap/zapcore"
+
+type Field = zapcore.Field
+
+func String(key string, val string) Field {
+	return Field{Key: key}
+}
+
+func Err(err error) Field {
+	return Field{Key: "error"}
+}
+
+type Logger interface {
+	Info(message string, kv ...interface{})
+	Error(message string, kv ...interface{})
+	With(kv ...interface{}) Logger
+}
@@ -0,0 +1,5 @@
+package zapcore
+
+type Field struct {
+	Key string
+}
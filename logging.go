@@ -1,6 +1,11 @@
 package app
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -10,21 +15,81 @@ type Logger interface {
 	Info(message string, kv ...interface{})
 	Warn(message string, kv ...interface{})
 	Error(message string, kv ...interface{})
+	DPanic(message string, kv ...interface{})
 	Panic(message string, kv ...interface{})
+	Fatal(message string, kv ...interface{})
+
+	// Enabled reports whether messages of the level are logged, to skip
+	// building expensive key-values.
+	Enabled(level LogLevel) bool
+
+	// The *Ctx methods add the key-values of the context, see WithLogFields
+	// and RegisterLogContextField.
+	DebugCtx(ctx context.Context, message string, kv ...interface{})
+	InfoCtx(ctx context.Context, message string, kv ...interface{})
+	WarnCtx(ctx context.Context, message string, kv ...interface{})
+	ErrorCtx(ctx context.Context, message string, kv ...interface{})
+
 	With(kv ...interface{}) Logger
 }
 
+// NamedLogger is implemented by the loggers able to derive a named logger,
+// the names joined with "." like "db.pool". The loggers of the app implement
+// it, and their levels can be set by name, see SetLogLevel.
+type NamedLogger interface {
+	Named(name string) Logger
+}
+
+// NameLogger returns the logger named after name when it implements
+// NamedLogger, otherwise the logger itself.
+func NameLogger(logger Logger, name string) Logger {
+
+	if named, ok := logger.(NamedLogger); ok {
+		return named.Named(name)
+	}
+
+	return logger
+
+}
+
+// LoggerConfig is the configuration of the logger read from the log.* keys.
+type LoggerConfig struct {
+	Level LogLevel
+	Json  bool
+	Dev   bool
+}
+
+// LoggerFactory builds a logger backed by something else than zap, see
+// AppOptions.
+type LoggerFactory func(config LoggerConfig) (Logger, error)
+
 type loggerBuilder[D any] struct {
 	*Injector[D]
 
 	*zap.Logger
 
-	LogLevel Config `config:"log.level,str" default:"debug" usage:"Log level (debug, info, error)"`
-	LogJson  Config `config:"log.json,bool" default:"false" usage:"Log in json format"`
-	LogDev   Config `config:"log.dev,bool" default:"true" usage:"Log in development mode"`
+	core   *swapCore
+	levels *logLevels
+
+	// appliedLevel is the last log.level applied to the levels, which reopen
+	// leaves alone while unchanged, keeping the level set at runtime
+	appliedLevel zapcore.Level
+
+	LogLevel Config `config:"log.level,str" default:"debug" reloadable:"true" usage:"Log level (debug, info, warn, error, dpanic, panic, fatal)"`
+	LogJson  Config `config:"log.json,bool" default:"false" reloadable:"true" usage:"Log in json format"`
+	LogDev   Config `config:"log.dev,bool" default:"true" reloadable:"true" usage:"Log in development mode"`
+
+	LogSamplingInitial    Config `config:"log.sampling.initial,int" reloadable:"true" usage:"Entries with the same level and message logged per tick before sampling (100 in production mode)"`
+	LogSamplingThereafter Config `config:"log.sampling.thereafter,int" reloadable:"true" usage:"Log every Nth entry once sampling (100 in production mode)"`
+	LogSamplingTick       Config `config:"log.sampling.tick,duration" reloadable:"true" usage:"Sampling period (1s by default)"`
+
+	LogRateLimitInterval Config `config:"log.rate.limit.interval,duration" reloadable:"true" usage:"Period to limit warnings and errors with the same message, disabled when empty"`
+	LogRateLimitBurst    Config `config:"log.rate.limit.burst,int" default:"1" reloadable:"true" usage:"Warnings and errors with the same message logged per period"`
+
+	Outputs []*logOutput `config:"log.outputs"`
 }
 
-func (l *loggerBuilder[D]) build() *stdLogger {
+func (l *loggerBuilder[D]) zapConfig() (zap.Config, error) {
 
 	zapConfig := zap.NewProductionConfig()
 
@@ -35,19 +100,17 @@ func (l *loggerBuilder[D]) build() *stdLogger {
 
 	zapConfig.EncoderConfig.EncodeTime = zapcore.RFC3339TimeEncoder
 
-	if l.LogLevel.IsSet() {
-		switch l.LogLevel.StringVal() {
+	zapConfig.Level.SetLevel(zap.DebugLevel)
 
-		case "error":
-			zapConfig.Level.SetLevel(zap.ErrorLevel)
+	if l.LogLevel.IsSet() {
 
-		case "info":
-			zapConfig.Level.SetLevel(zap.InfoLevel)
+		level, err := LogLevel(l.LogLevel.StringVal()).zapLevel()
+		if err != nil {
+			return zapConfig, fmt.Errorf("invalid value for 'log.level': %w", err)
+		}
 
-		default:
-			zapConfig.Level.SetLevel(zap.DebugLevel)
+		zapConfig.Level.SetLevel(level)
 
-		}
 	}
 
 	zapConfig.Encoding = "console"
@@ -56,45 +119,342 @@ func (l *loggerBuilder[D]) build() *stdLogger {
 		zapConfig.Encoding = "json"
 	}
 
-	logger, err := zapConfig.Build(
-		zap.AddCallerSkip(1),
-	)
+	return zapConfig, nil
+
+}
+
+func (l *loggerBuilder[D]) loggerConfig() (LoggerConfig, error) {
+
+	zapConfig, err := l.zapConfig()
+	if err != nil {
+		return LoggerConfig{}, err
+	}
+
+	return LoggerConfig{
+		Level: LogLevel(zapConfig.Level.Level().String()),
+		Json:  zapConfig.Encoding == "json",
+		Dev:   zapConfig.Development,
+	}, nil
+
+}
+
+// ValidateConfig rejects a reloaded log.level that isn't a zap level.
+func (l *loggerBuilder[D]) ValidateConfig() error {
+
+	_, err := l.zapConfig()
+
+	return err
+
+}
+
+func (l *loggerBuilder[D]) build() *stdLogger {
+
+	zapConfig, err := l.zapConfig()
+	if err != nil {
+		panic(err)
+	}
+
+	l.core = &swapCore{root: &atomic.Pointer[swapRoot]{}}
+	l.levels = newLogLevels(zapConfig.Level.Level())
+	l.appliedLevel = zapConfig.Level.Level()
+
+	if err := l.reopen(); err != nil {
+		panic(err)
+	}
+
+	errSink, _, err := zap.Open(zapConfig.ErrorOutputPaths...)
 	if err != nil {
 		panic(err)
 	}
 
+	opts := []zap.Option{
+		zap.ErrorOutput(errSink),
+		zap.AddCaller(),
+		zap.AddCallerSkip(1),
+	}
+
+	if zapConfig.Development {
+		opts = append(opts, zap.Development(), zap.AddStacktrace(zap.WarnLevel))
+	} else {
+		opts = append(opts, zap.AddStacktrace(zap.ErrorLevel))
+	}
+
+	logger := zap.New(l.core, opts...)
+
 	l.Logger = logger
 
-	return &stdLogger{logger.Sugar()}
+	return newStdLogger(logger, l.levels, "")
+
+}
+
+// reopen builds the logger core from the current configuration, opening the
+// outputs again, and closes the outputs of the previous core. The levels are
+// shared between the cores, so they can be changed while running.
+func (l *loggerBuilder[D]) reopen() error {
+
+	zapConfig, err := l.zapConfig()
+	if err != nil {
+		return err
+	}
+
+	core, closeSink, err := l.openOutputs(zapConfig)
+	if err != nil {
+		return err
+	}
+
+	if level := zapConfig.Level.Level(); level != l.appliedLevel {
+		l.levels.set("", level)
+		l.appliedLevel = level
+	}
+
+	if sampling, tick := l.samplingConfig(zapConfig); sampling != nil {
+		core = &deferredCheckCore{zapcore.NewSamplerWithOptions(core, tick, sampling.Initial, sampling.Thereafter)}
+	}
+
+	if l.LogRateLimitInterval.DurationVal() > 0 {
+		core = newRateLimitCore(core, l.LogRateLimitInterval.DurationVal(), int(l.LogRateLimitBurst.Int64Val()))
+	}
+
+	core = &leveledCore{core, l.levels}
+
+	prev := l.core.root.Swap(&swapRoot{core: core, close: closeSink})
+
+	if prev != nil {
+		prev.core.Sync()
+		prev.close()
+	}
+
+	return nil
+
+}
+
+type swapRoot struct {
+	core  zapcore.Core
+	close func()
+}
+
+type swapDerived struct {
+	root *swapRoot
+	core zapcore.Core
+}
+
+// swapCore forwards to a core that can be replaced while logging, keeping the
+// fields added with With, so the loggers derived from the app logger follow
+// configuration reloads.
+type swapCore struct {
+	root    *atomic.Pointer[swapRoot]
+	fields  []zapcore.Field
+	derived atomic.Pointer[swapDerived]
+}
+
+func (c *swapCore) current() zapcore.Core {
+
+	root := c.root.Load()
+
+	if len(c.fields) == 0 {
+		return root.core
+	}
+
+	if derived := c.derived.Load(); derived != nil && derived.root == root {
+		return derived.core
+	}
+
+	derived := &swapDerived{root: root, core: root.core.With(c.fields)}
+	c.derived.Store(derived)
+
+	return derived.core
+
+}
+
+func (c *swapCore) Enabled(level zapcore.Level) bool {
+	return c.current().Enabled(level)
+}
+
+func (c *swapCore) With(fields []zapcore.Field) zapcore.Core {
+	return &swapCore{
+		root:   c.root,
+		fields: append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *swapCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return c.current().Check(ent, ce)
+}
+
+func (c *swapCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.current().Write(ent, fields)
+}
+
+func (c *swapCore) Sync() error {
+	return c.current().Sync()
+}
+
+// customLogger is the Logger of AppOptions, synced when it's able to and
+// untouched by reloads.
+type customLogger struct {
+	Logger
+}
+
+func (c *customLogger) Sync() error {
+
+	if syncer, ok := c.Logger.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+
+	return nil
+
+}
+
+func (c *customLogger) Named(name string) Logger {
+	return NameLogger(c.Logger, name)
+}
 
+func (c *customLogger) reopen() error {
+	return nil
 }
 
 type stdLogger struct {
 	logger *zap.SugaredLogger
+
+	// desugared logs the messages whose key-values are all fields
+	desugared *zap.Logger
+
+	// levels and name answer Enabled for the loggers of the app, whose core
+	// only knows the lowest level of all the names
+	levels *logLevels
+	name   string
+}
+
+func newStdLogger(logger *zap.Logger, levels *logLevels, name string) *stdLogger {
+	return &stdLogger{logger: logger.Sugar(), desugared: logger, levels: levels, name: name}
+}
+
+// NewZapLogger returns a Logger writing to the zap logger, for apps and tests
+// building their own zap core.
+func NewZapLogger(logger *zap.Logger) Logger {
+	return newStdLogger(logger.WithOptions(zap.AddCallerSkip(1)), nil, "")
 }
 
 func (s *stdLogger) Debug(message string, kv ...interface{}) {
+
+	if fields, ok := typedFields(kv); ok {
+		s.desugared.Debug(message, fields...)
+		return
+	}
+
 	s.logger.Debugw(message, kv...)
+
 }
 
 func (s *stdLogger) Info(message string, kv ...interface{}) {
+
+	if fields, ok := typedFields(kv); ok {
+		s.desugared.Info(message, fields...)
+		return
+	}
+
 	s.logger.Infow(message, kv...)
+
 }
 
 func (s *stdLogger) Warn(message string, kv ...interface{}) {
+
+	if fields, ok := typedFields(kv); ok {
+		s.desugared.Warn(message, fields...)
+		return
+	}
+
 	s.logger.Warnw(message, kv...)
+
 }
 
 func (s *stdLogger) Error(message string, kv ...interface{}) {
+
+	if fields, ok := typedFields(kv); ok {
+		s.desugared.Error(message, fields...)
+		return
+	}
+
 	s.logger.Errorw(message, kv...)
+
+}
+
+func (s *stdLogger) DPanic(message string, kv ...interface{}) {
+
+	if fields, ok := typedFields(kv); ok {
+		s.desugared.DPanic(message, fields...)
+		return
+	}
+
+	s.logger.DPanicw(message, kv...)
+
 }
 
 func (s *stdLogger) Panic(message string, kv ...interface{}) {
+
+	if fields, ok := typedFields(kv); ok {
+		s.desugared.Panic(message, fields...)
+		return
+	}
+
 	s.logger.Panicw(message, kv...)
+
+}
+
+func (s *stdLogger) Fatal(message string, kv ...interface{}) {
+
+	if fields, ok := typedFields(kv); ok {
+		s.desugared.Fatal(message, fields...)
+		return
+	}
+
+	s.logger.Fatalw(message, kv...)
+
+}
+
+func (s *stdLogger) Enabled(level LogLevel) bool {
+
+	zapLevel, err := level.zapLevel()
+	if err != nil {
+		return false
+	}
+
+	// Checking an entry would count it in the sampler and the rate limiter
+	if !s.desugared.Core().Enabled(zapLevel) {
+		return false
+	}
+
+	if s.levels != nil {
+		return s.levels.enabledFor(s.name, zapLevel)
+	}
+
+	return true
+
 }
 
 func (s *stdLogger) With(kv ...interface{}) Logger {
-	return &stdLogger{s.logger.With(kv...)}
+
+	if fields, ok := typedFields(kv); ok {
+		return newStdLogger(s.desugared.With(fields...), s.levels, s.name)
+	}
+
+	return newStdLogger(s.logger.With(kv...).Desugar(), s.levels, s.name)
+
+}
+
+func (s *stdLogger) Named(name string) Logger {
+
+	named := newStdLogger(s.desugared.Named(name), s.levels, name)
+
+	// Same as zap joins the names
+	if len(name) == 0 {
+		named.name = s.name
+	} else if len(s.name) > 0 {
+		named.name = strings.Join([]string{s.name, name}, ".")
+	}
+
+	return named
+
 }
 
 func (s *stdLogger) Sync() error {
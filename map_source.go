@@ -0,0 +1,464 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MapSource serves configuration values from memory, set from nested maps and
+// slices or with json paths like "db.replicas.0.addr", and changed at runtime
+// with Set and Delete.
+//
+// A path holds either a value or nested values: setting a value replaces the
+// nested values under its path, and setting a nested path replaces the value of
+// its parent.
+type MapSource struct {
+	mu        sync.RWMutex
+	root      map[string]interface{}
+	listeners []func(key string)
+}
+
+// NewMapSource sets the values in a defined order, the shorter paths first and
+// then by name, so "tls.cert" is nested under "tls" replacing its value.
+func NewMapSource(values map[string]interface{}) *MapSource {
+
+	m := &MapSource{
+		root: make(map[string]interface{}),
+	}
+
+	m.setAll("", values)
+
+	return m
+
+}
+
+func (m *MapSource) Load() error {
+	return nil
+}
+
+// Get returns the value at the path, the JSON of nested maps and slices.
+func (m *MapSource) Get(k string) Config {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node, ok := m.lookup(k)
+	if !ok {
+		return EmptyConfig()
+	}
+
+	switch typedNode := node.(type) {
+
+	case nil:
+
+		return EmptyConfig()
+
+	case Config:
+
+		return typedNode
+
+	case map[string]interface{}, []interface{}:
+
+		raw, err := json.Marshal(plainMapValue(typedNode))
+		if err != nil {
+			return EmptyConfig()
+		}
+
+		return NewConfig(string(raw))
+
+	}
+
+	return NewConfig(node)
+
+}
+
+// Has reports whether the path was set, even to a nested map or to nil.
+func (m *MapSource) Has(k string) bool {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, ok := m.lookup(k)
+
+	return ok
+
+}
+
+func (m *MapSource) Keys(prefix string) []string {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node, ok := m.lookup(prefix)
+	if !ok {
+		return []string{}
+	}
+
+	keys := []string{}
+
+	switch typedNode := node.(type) {
+
+	case map[string]interface{}:
+
+		for key := range typedNode {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+	case []interface{}:
+
+		for i := range typedNode {
+			keys = append(keys, strconv.Itoa(i))
+		}
+
+	}
+
+	return keys
+
+}
+
+// Set sets the value at the path, creating the missing maps on the way and
+// replacing the values in the way, but merging nested maps into the existing
+// ones, then notifies the change.
+func (m *MapSource) Set(key string, val interface{}) {
+
+	m.mu.Lock()
+	m.set(key, val)
+	m.mu.Unlock()
+
+	m.notify(key)
+
+}
+
+// Delete removes the value at the path, shifting the next elements when it's
+// in a slice, then notifies the change.
+func (m *MapSource) Delete(key string) {
+
+	m.mu.Lock()
+
+	parentKey, last := splitLastConfigKey(key)
+
+	deleted := false
+
+	if parent, ok := m.lookup(parentKey); ok {
+
+		switch typedParent := parent.(type) {
+
+		case map[string]interface{}:
+
+			if _, deleted = typedParent[last]; deleted {
+				delete(typedParent, last)
+			}
+
+		case []interface{}:
+
+			if i, err := strconv.Atoi(last); err == nil && i >= 0 && i < len(typedParent) {
+				m.replace(parentKey, append(typedParent[:i:i], typedParent[i+1:]...))
+				deleted = true
+			}
+
+		}
+
+	}
+
+	m.mu.Unlock()
+
+	if deleted {
+		m.notify(key)
+	}
+
+}
+
+// OnChange registers a function called with the path of every Set and Delete.
+func (m *MapSource) OnChange(fn func(key string)) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.listeners = append(m.listeners, fn)
+
+}
+
+func (m *MapSource) notify(key string) {
+
+	m.mu.RLock()
+	listeners := m.listeners
+	m.mu.RUnlock()
+
+	for _, fn := range listeners {
+		fn(key)
+	}
+
+}
+
+func (m *MapSource) lookup(key string) (interface{}, bool) {
+
+	var node interface{} = m.root
+
+	if len(key) == 0 {
+		return node, true
+	}
+
+	for _, segment := range strings.Split(key, ".") {
+
+		switch typedNode := node.(type) {
+
+		case map[string]interface{}:
+
+			child, ok := typedNode[segment]
+			if !ok {
+				return nil, false
+			}
+
+			node = child
+
+		case []interface{}:
+
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(typedNode) {
+				return nil, false
+			}
+
+			node = typedNode[i]
+
+		default:
+
+			return nil, false
+
+		}
+
+	}
+
+	return node, true
+
+}
+
+func (m *MapSource) set(key string, val interface{}) {
+
+	val = normalizeMapValue(val)
+
+	// Keys like "db.dsn" in a nested map are paths too, merged into the
+	// existing map
+	if nested, ok := val.(map[string]interface{}); ok {
+
+		if current, _ := m.lookup(key); !isMapNode(current) {
+			m.replace(key, make(map[string]interface{}))
+		}
+
+		m.setAll(key, nested)
+
+		return
+
+	}
+
+	m.replace(key, val)
+
+}
+
+// setAll sets the values under the prefix, the shorter paths first.
+func (m *MapSource) setAll(prefix string, values map[string]interface{}) {
+
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+
+		depthI, depthJ := strings.Count(keys[i], "."), strings.Count(keys[j], ".")
+
+		if depthI != depthJ {
+			return depthI < depthJ
+		}
+
+		return keys[i] < keys[j]
+
+	})
+
+	for _, key := range keys {
+		m.set(joinConfigKey(prefix, key), values[key])
+	}
+
+}
+
+// replace sets the value at the path, only a map replacing the root.
+func (m *MapSource) replace(key string, val interface{}) {
+
+	if len(key) == 0 {
+
+		if nested, ok := val.(map[string]interface{}); ok {
+			m.root = nested
+		}
+
+		return
+
+	}
+
+	parentKey, last := splitLastConfigKey(key)
+
+	parent, _ := m.lookup(parentKey)
+
+	switch typedParent := parent.(type) {
+
+	case map[string]interface{}:
+
+		typedParent[last] = val
+		return
+
+	case []interface{}:
+
+		if i, err := strconv.Atoi(last); err == nil && i >= 0 {
+
+			if i >= len(typedParent) {
+				typedParent = append(typedParent, make([]interface{}, i+1-len(typedParent))...)
+			}
+
+			typedParent[i] = val
+
+			m.replace(parentKey, typedParent)
+
+			return
+
+		}
+
+	}
+
+	// The parent is missing or isn't a map nor a slice
+	m.replace(parentKey, map[string]interface{}{last: val})
+
+}
+
+func isMapNode(node interface{}) bool {
+
+	_, ok := node.(map[string]interface{})
+
+	return ok
+
+}
+
+func splitLastConfigKey(key string) (string, string) {
+
+	if sep := strings.LastIndex(key, "."); sep >= 0 {
+		return key[:sep], key[sep+1:]
+	}
+
+	return "", key
+
+}
+
+// normalizeMapValue turns any map with string keys into map[string]interface{},
+// any slice but []string into []interface{} and the scalars into the types
+// NewConfig reads.
+func normalizeMapValue(val interface{}) interface{} {
+
+	switch typedVal := val.(type) {
+
+	case nil, Config, string, []string, time.Duration, time.Time:
+		return val
+
+	case []byte:
+		return string(typedVal)
+
+	}
+
+	v := reflect.ValueOf(val)
+
+	switch v.Kind() {
+
+	case reflect.Map:
+
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		nested := make(map[string]interface{}, v.Len())
+
+		iter := v.MapRange()
+		for iter.Next() {
+			nested[iter.Key().String()] = normalizeMapValue(iter.Value().Interface())
+		}
+
+		return nested
+
+	case reflect.Slice, reflect.Array:
+
+		nested := make([]interface{}, v.Len())
+
+		for i := range nested {
+			nested[i] = normalizeMapValue(v.Index(i).Interface())
+		}
+
+		return nested
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		return v.Int()
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+
+		return strconv.FormatUint(v.Uint(), 10)
+
+	case reflect.Float32, reflect.Float64:
+
+		return v.Float()
+
+	case reflect.Bool:
+
+		return v.Bool()
+
+	}
+
+	return fmt.Sprint(val)
+
+}
+
+// plainMapValue replaces the Config values by their strings to marshal them.
+func plainMapValue(val interface{}) interface{} {
+
+	switch typedVal := val.(type) {
+
+	case Config:
+
+		if !typedVal.IsSet() {
+			return nil
+		}
+
+		return typedVal.StringVal()
+
+	case time.Duration:
+
+		return typedVal.String()
+
+	case map[string]interface{}:
+
+		plain := make(map[string]interface{}, len(typedVal))
+
+		for k, v := range typedVal {
+			plain[k] = plainMapValue(v)
+		}
+
+		return plain
+
+	case []interface{}:
+
+		plain := make([]interface{}, len(typedVal))
+
+		for i, v := range typedVal {
+			plain[i] = plainMapValue(v)
+		}
+
+		return plain
+
+	}
+
+	return val
+
+}
@@ -0,0 +1,124 @@
+package app_test
+
+import (
+	"testing"
+	"time"
+
+	app "github.com/protomesh/go-app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapSourceLookups(t *testing.T) {
+
+	src := app.NewMapSource(map[string]interface{}{
+		"db.dsn": "postgres://localhost/pets",
+		"db": map[string]interface{}{
+			"timeout": 5 * time.Second,
+			"pool":    map[string]int32{"size": 8},
+			"replicas": []map[string]interface{}{
+				{"addr": "replica-0"},
+				{"addr": "replica-1"},
+			},
+		},
+		"tags":  []string{"a", "b"},
+		"debug": true,
+		"unset": nil,
+	})
+
+	assert.NoError(t, src.Load())
+
+	assert.Equal(t, "postgres://localhost/pets", src.Get("db.dsn").StringVal())
+	assert.Equal(t, 5*time.Second, src.Get("db.timeout").DurationVal())
+	assert.Equal(t, int64(8), src.Get("db.pool.size").Int64Val())
+	assert.Equal(t, "replica-1", src.Get("db.replicas.1.addr").StringVal())
+	assert.Equal(t, []string{"a", "b"}, src.Get("tags").StringSliceVal())
+	assert.True(t, src.Get("debug").BoolVal())
+	assert.JSONEq(t, `{"size":8}`, src.Get("db.pool").StringVal())
+
+	assert.True(t, src.Has("db"))
+	assert.True(t, src.Has("unset"))
+	assert.False(t, src.Get("unset").IsSet())
+	assert.False(t, src.Has("db.replicas.2"))
+	assert.False(t, src.Has("db.dsn.host"))
+
+	assert.Equal(t, []string{"dsn", "pool", "replicas", "timeout"}, src.Keys("db"))
+	assert.Equal(t, []string{"0", "1"}, src.Keys("db.replicas"))
+	assert.Empty(t, src.Keys("db.dsn"))
+
+}
+
+func TestMapSourceNestsLongerPaths(t *testing.T) {
+
+	for i := 0; i < 20; i++ {
+
+		src := app.NewMapSource(map[string]interface{}{
+			"tls.cert": "cert.pem",
+			"tls":      "on",
+			"db":       map[string]interface{}{"pool": 4, "pool.size": 8},
+		})
+
+		assert.JSONEq(t, `{"cert":"cert.pem"}`, src.Get("tls").StringVal())
+		assert.Equal(t, "cert.pem", src.Get("tls.cert").StringVal())
+		assert.Equal(t, int64(8), src.Get("db.pool.size").Int64Val())
+
+	}
+
+}
+
+func TestMapSourceSetAndDelete(t *testing.T) {
+
+	src := app.NewMapSource(map[string]interface{}{
+		"db": map[string]interface{}{
+			"replicas": []interface{}{"replica-0", "replica-1"},
+		},
+	})
+
+	changed := []string{}
+	src.OnChange(func(key string) {
+		changed = append(changed, key)
+	})
+
+	src.Set("db.pool.size", 4)
+	src.Set("db.replicas.2", "replica-2")
+	src.Delete("db.replicas.0")
+	src.Delete("db.missing")
+
+	assert.Equal(t, int64(4), src.Get("db.pool.size").Int64Val())
+	assert.Equal(t, []string{"0", "1"}, src.Keys("db.replicas"))
+	assert.Equal(t, "replica-2", src.Get("db.replicas.1").StringVal())
+	assert.Equal(t, []string{"db.pool.size", "db.replicas.2", "db.replicas.0"}, changed)
+
+	src.Delete("db")
+
+	assert.False(t, src.Has("db.pool.size"))
+	assert.Empty(t, src.Keys(""))
+
+}
+
+func TestCompositeSourceFollowsMapSource(t *testing.T) {
+
+	overrides := app.NewMapSource(nil)
+
+	src := app.NewCompositeSource(overrides, app.NewMapSource(map[string]interface{}{
+		"log.level": "info",
+	}))
+
+	assert.NoError(t, src.Load())
+
+	assert.Equal(t, "info", src.Get("log.level").StringVal())
+
+	overrides.Set("log.level", "debug")
+
+	assert.Equal(t, "debug", src.Get("log.level").StringVal())
+
+	overrides.Delete("log.level")
+
+	assert.Equal(t, "info", src.Get("log.level").StringVal())
+	assert.True(t, src.Has("log.level"))
+
+	overrides.Set("log", map[string]interface{}{"json": true})
+
+	assert.Equal(t, []string{"json", "level"}, src.(app.ConfigKeyLister).Keys("log"))
+
+}
@@ -0,0 +1,397 @@
+package app
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	protoDurationName  protoreflect.FullName = "google.protobuf.Duration"
+	protoTimestampName protoreflect.FullName = "google.protobuf.Timestamp"
+)
+
+var protoWrapperNames = map[protoreflect.FullName]bool{
+	"google.protobuf.DoubleValue": true,
+	"google.protobuf.FloatValue":  true,
+	"google.protobuf.Int64Value":  true,
+	"google.protobuf.UInt64Value": true,
+	"google.protobuf.Int32Value":  true,
+	"google.protobuf.UInt32Value": true,
+	"google.protobuf.BoolValue":   true,
+	"google.protobuf.StringValue": true,
+	"google.protobuf.BytesValue":  true,
+}
+
+func isProtoMessageType(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && t.Implements(reflect.TypeOf((*proto.Message)(nil)).Elem())
+}
+
+func protoDescriptorOf(t reflect.Type) protoreflect.MessageDescriptor {
+	return reflect.New(t.Elem()).Interface().(proto.Message).ProtoReflect().Descriptor()
+}
+
+func protoFieldKey(prefix string, fd protoreflect.FieldDescriptor) string {
+	return joinConfigKey(prefix, ConvertKeyCase(string(fd.Name()), JsonPathCase))
+}
+
+// protoLeafType returns the flag type specifier of fields bound to a single
+// configuration key, or an empty string for fields only read from files.
+func protoLeafType(fd protoreflect.FieldDescriptor) string {
+
+	if fd.IsMap() {
+		return ""
+	}
+
+	if fd.IsList() {
+
+		if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+			return ""
+		}
+
+		return "str"
+
+	}
+
+	switch fd.Kind() {
+
+	case protoreflect.BoolKind:
+		return "bool"
+
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "int64"
+
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return "float64"
+
+	case protoreflect.StringKind, protoreflect.BytesKind, protoreflect.EnumKind:
+		return "str"
+
+	case protoreflect.MessageKind:
+
+		switch name := fd.Message().FullName(); {
+
+		case name == protoDurationName:
+			return "duration"
+
+		case name == protoTimestampName:
+			return "datetime"
+
+		case protoWrapperNames[name]:
+			return protoLeafType(fd.Message().Fields().ByName("value"))
+
+		}
+
+	}
+
+	return ""
+
+}
+
+func (ao *AppOptions) defineProtoFlags(prefix string, desc protoreflect.MessageDescriptor, path map[protoreflect.FullName]bool) {
+
+	if path[desc.FullName()] {
+		return
+	}
+
+	path[desc.FullName()] = true
+	defer delete(path, desc.FullName())
+
+	fields := desc.Fields()
+
+	for i := 0; i < fields.Len(); i++ {
+
+		fd := fields.Get(i)
+		key := protoFieldKey(prefix, fd)
+
+		if flagType := protoLeafType(fd); len(flagType) > 0 {
+			ao.defineFlag(key, flagType, fmt.Sprintf("Protobuf field %s", fd.FullName()), "")
+			continue
+		}
+
+		if fd.Kind() == protoreflect.MessageKind && !fd.IsList() && !fd.IsMap() {
+			ao.defineProtoFlags(key, fd.Message(), path)
+		}
+
+	}
+
+}
+
+func (ao *AppOptions) applyProtoConfig(key string, m proto.Message, res *applyResult) {
+
+	proto.Reset(m)
+
+	if ao.Source.Has(key) {
+
+		raw := strings.TrimSpace(ao.Source.Get(key).StringVal())
+
+		if strings.HasPrefix(raw, "{") {
+
+			err := protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal([]byte(raw), m)
+			if err != nil {
+				res.errs = append(res.errs, fmt.Errorf("invalid protobuf configuration '%s': %w", key, err))
+				return
+			}
+
+		}
+
+	}
+
+	msg := m.ProtoReflect()
+
+	ao.overlayProtoMessage(key, msg.Descriptor(), func() protoreflect.Message { return msg }, make(map[protoreflect.FullName]bool), res)
+
+	ao.getState().recordProtoConfig(key, m)
+
+	if ao.tw != nil {
+		ao.tw.AppendRow(table.Row{key, fmt.Sprintf("%T", m), protojson.Format(m)})
+	}
+
+}
+
+// overlayProtoMessage sets the fields found under the prefix, allocating the
+// nested messages only when one of their fields is set.
+func (ao *AppOptions) overlayProtoMessage(prefix string, desc protoreflect.MessageDescriptor, mutable func() protoreflect.Message, path map[protoreflect.FullName]bool, res *applyResult) {
+
+	if path[desc.FullName()] {
+		return
+	}
+
+	path[desc.FullName()] = true
+	defer delete(path, desc.FullName())
+
+	fields := desc.Fields()
+
+	for i := 0; i < fields.Len(); i++ {
+
+		fd := fields.Get(i)
+		key := protoFieldKey(prefix, fd)
+
+		if len(protoLeafType(fd)) == 0 {
+
+			if fd.Kind() == protoreflect.MessageKind && !fd.IsList() && !fd.IsMap() {
+				ao.overlayProtoMessage(key, fd.Message(), func() protoreflect.Message {
+					return mutable().Mutable(fd).Message()
+				}, path, res)
+			}
+
+			continue
+
+		}
+
+		if !ao.Source.Has(key) {
+			continue
+		}
+
+		if err := setProtoField(mutable(), fd, ao.Source.Get(key)); err != nil {
+			res.errs = append(res.errs, fmt.Errorf("invalid value for '%s': %w", key, err))
+		}
+
+	}
+
+}
+
+func setProtoField(m protoreflect.Message, fd protoreflect.FieldDescriptor, cfg Config) error {
+
+	if fd.IsList() {
+
+		items := []string{}
+		raw := strings.TrimSpace(cfg.StringVal())
+
+		if strings.HasPrefix(raw, "[") {
+
+			values := []interface{}{}
+
+			if err := json.Unmarshal([]byte(raw), &values); err != nil {
+				return err
+			}
+
+			for _, val := range values {
+				items = append(items, fmt.Sprint(val))
+			}
+
+		} else if len(raw) > 0 {
+
+			for _, item := range strings.Split(raw, ",") {
+				items = append(items, strings.TrimSpace(item))
+			}
+
+		}
+
+		list := m.Mutable(fd).List()
+		list.Truncate(0)
+
+		for _, item := range items {
+
+			val, err := parseProtoScalar(fd, item)
+			if err != nil {
+				return err
+			}
+
+			list.Append(val)
+
+		}
+
+		return nil
+
+	}
+
+	if fd.Kind() == protoreflect.MessageKind {
+
+		sub := m.Mutable(fd).Message()
+		subFields := sub.Descriptor().Fields()
+
+		switch name := fd.Message().FullName(); {
+
+		case name == protoDurationName:
+
+			d, err := time.ParseDuration(cfg.StringVal())
+			if err != nil {
+				return err
+			}
+
+			sub.Set(subFields.ByName("seconds"), protoreflect.ValueOfInt64(int64(d/time.Second)))
+			sub.Set(subFields.ByName("nanos"), protoreflect.ValueOfInt32(int32(d%time.Second)))
+
+		case name == protoTimestampName:
+
+			t, err := time.Parse(time.RFC3339Nano, cfg.StringVal())
+			if err != nil {
+				return err
+			}
+
+			sub.Set(subFields.ByName("seconds"), protoreflect.ValueOfInt64(t.Unix()))
+			sub.Set(subFields.ByName("nanos"), protoreflect.ValueOfInt32(int32(t.Nanosecond())))
+
+		default:
+
+			valueFd := subFields.ByName("value")
+
+			val, err := parseProtoScalar(valueFd, cfg.StringVal())
+			if err != nil {
+				return err
+			}
+
+			sub.Set(valueFd, val)
+
+		}
+
+		return nil
+
+	}
+
+	val, err := parseProtoScalar(fd, cfg.StringVal())
+	if err != nil {
+		return err
+	}
+
+	m.Set(fd, val)
+
+	return nil
+
+}
+
+func parseProtoScalar(fd protoreflect.FieldDescriptor, raw string) (protoreflect.Value, error) {
+
+	switch fd.Kind() {
+
+	case protoreflect.BoolKind:
+
+		val, err := strconv.ParseBool(raw)
+		if err != nil {
+
+			switch strings.ToLower(raw) {
+			case "y", "yes":
+				return protoreflect.ValueOfBool(true), nil
+			case "n", "no", "not":
+				return protoreflect.ValueOfBool(false), nil
+			}
+
+			return protoreflect.Value{}, err
+
+		}
+
+		return protoreflect.ValueOfBool(val), nil
+
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+
+		val, err := strconv.ParseInt(raw, 10, 32)
+
+		return protoreflect.ValueOfInt32(int32(val)), err
+
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+
+		val, err := strconv.ParseInt(raw, 10, 64)
+
+		return protoreflect.ValueOfInt64(val), err
+
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+
+		val, err := strconv.ParseUint(raw, 10, 32)
+
+		return protoreflect.ValueOfUint32(uint32(val)), err
+
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+
+		val, err := strconv.ParseUint(raw, 10, 64)
+
+		return protoreflect.ValueOfUint64(val), err
+
+	case protoreflect.FloatKind:
+
+		val, err := strconv.ParseFloat(raw, 32)
+
+		return protoreflect.ValueOfFloat32(float32(val)), err
+
+	case protoreflect.DoubleKind:
+
+		val, err := strconv.ParseFloat(raw, 64)
+
+		return protoreflect.ValueOfFloat64(val), err
+
+	case protoreflect.StringKind:
+
+		return protoreflect.ValueOfString(raw), nil
+
+	case protoreflect.BytesKind:
+
+		val, err := base64.StdEncoding.DecodeString(raw)
+
+		return protoreflect.ValueOfBytes(val), err
+
+	case protoreflect.EnumKind:
+
+		values := fd.Enum().Values()
+
+		if val := values.ByName(protoreflect.Name(raw)); val != nil {
+			return protoreflect.ValueOfEnum(val.Number()), nil
+		}
+
+		if val := values.ByName(protoreflect.Name(strings.ToUpper(raw))); val != nil {
+			return protoreflect.ValueOfEnum(val.Number()), nil
+		}
+
+		num, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("unknown %s value '%s'", fd.Enum().FullName(), raw)
+		}
+
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(num)), nil
+
+	}
+
+	return protoreflect.Value{}, fmt.Errorf("unsupported field kind %s", fd.Kind())
+
+}
@@ -0,0 +1,358 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type ProtoJson_FileEventType string
+
+const (
+	ProtoJson_FileAdded   ProtoJson_FileEventType = "added"
+	ProtoJson_FileUpdated ProtoJson_FileEventType = "updated"
+	ProtoJson_FileDeleted ProtoJson_FileEventType = "deleted"
+)
+
+const defaultProtoJsonDirInterval = 5 * time.Second
+
+// ProtoJsonDirError aggregates the errors of the files that failed to load,
+// keyed by their path.
+type ProtoJsonDirError struct {
+	Errors map[string]error
+}
+
+func (e *ProtoJsonDirError) Error() string {
+
+	paths := make([]string, 0, len(e.Errors))
+	for path := range e.Errors {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	msgs := make([]string, 0, len(paths))
+	for _, path := range paths {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", path, e.Errors[path]))
+	}
+
+	return strings.Join(msgs, "\n")
+
+}
+
+func (e *ProtoJsonDirError) Unwrap() []error {
+
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+
+	return errs
+
+}
+
+type ProtoJsonFileEvent[M proto.Message] struct {
+	Type    ProtoJson_FileEventType
+	Path    string
+	Message M
+	Err     error
+}
+
+// ProtoJsonDirLoader decodes every JSON, YAML and TOML file under Root into a
+// message created by NewMessage, one message per file. Symlinks to files are
+// followed, symlinks to directories aren't. Use os.DirFS to load a directory
+// of the local file system.
+type ProtoJsonDirLoader[M proto.Message] struct {
+	FS         fs.FS
+	Root       string
+	NewMessage func() M
+	Options    *ProtoJsonUnmarshalOptions
+
+	// Interval between scans when watching, defaults to 5 seconds.
+	Interval time.Duration
+}
+
+type protoJsonFileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// protoJsonDirState is what a watch saw in the previous scan: the stamps of the
+// files and the errors already reported for the paths that failed to scan.
+type protoJsonDirState struct {
+	known  map[string]protoJsonFileStamp
+	failed map[string]string
+}
+
+func (l *ProtoJsonDirLoader[M]) root() string {
+
+	if len(l.Root) == 0 {
+		return "."
+	}
+
+	return l.Root
+
+}
+
+// Load returns the messages decoded successfully keyed by path, along with a
+// *ProtoJsonDirError when any file failed.
+func (l *ProtoJsonDirLoader[M]) Load() (map[string]M, error) {
+
+	msgs := make(map[string]M)
+	errs := make(map[string]error)
+
+	stamps, err := l.scan(errs)
+	if err != nil {
+		return msgs, err
+	}
+
+	for path := range stamps {
+
+		m, err := l.decode(path)
+		if err != nil {
+			errs[path] = err
+			continue
+		}
+
+		msgs[path] = m
+
+	}
+
+	if len(errs) > 0 {
+		return msgs, &ProtoJsonDirError{Errors: errs}
+	}
+
+	return msgs, nil
+
+}
+
+// Watch emits an added event for every file found in the first scan, then
+// polls for added, updated and deleted files until the context is done. The
+// errors are reported once, until the failing file or its error changes.
+func (l *ProtoJsonDirLoader[M]) Watch(ctx context.Context) <-chan ProtoJsonFileEvent[M] {
+
+	interval := l.Interval
+	if interval <= 0 {
+		interval = defaultProtoJsonDirInterval
+	}
+
+	eventCh := make(chan ProtoJsonFileEvent[M])
+
+	go func() {
+
+		defer close(eventCh)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		state := &protoJsonDirState{
+			known:  make(map[string]protoJsonFileStamp),
+			failed: make(map[string]string),
+		}
+
+		for {
+
+			for _, ev := range l.diff(state) {
+
+				select {
+				case eventCh <- ev:
+				case <-ctx.Done():
+					return
+				}
+
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+		}
+
+	}()
+
+	return eventCh
+
+}
+
+// diff returns the events since the previous scan. The files failing to
+// decode are known by their stamp, and the paths failing to scan by their
+// error, so their errors are only reported again once they change.
+func (l *ProtoJsonDirLoader[M]) diff(state *protoJsonDirState) []ProtoJsonFileEvent[M] {
+
+	events := []ProtoJsonFileEvent[M]{}
+	errs := make(map[string]error)
+
+	known := state.known
+
+	stamps, err := l.scan(errs)
+	if err != nil {
+		errs = map[string]error{l.root(): err}
+	}
+
+	errPaths := make([]string, 0, len(errs))
+	for path := range errs {
+		errPaths = append(errPaths, path)
+	}
+
+	sort.Strings(errPaths)
+
+	for _, path := range errPaths {
+
+		err := errs[path]
+
+		if state.failed[path] == err.Error() {
+			continue
+		}
+
+		state.failed[path] = err.Error()
+
+		events = append(events, ProtoJsonFileEvent[M]{Path: path, Err: err})
+
+	}
+
+	for path := range state.failed {
+		if _, ok := errs[path]; !ok {
+			delete(state.failed, path)
+		}
+	}
+
+	// The known files are kept until the root can be scanned again
+	if _, ok := errs[l.root()]; ok {
+		return events
+	}
+
+	paths := make([]string, 0, len(stamps))
+	for path := range stamps {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	for _, path := range paths {
+
+		stamp := stamps[path]
+
+		evType := ProtoJson_FileUpdated
+
+		if prev, ok := known[path]; !ok {
+			evType = ProtoJson_FileAdded
+		} else if prev == stamp {
+			continue
+		}
+
+		known[path] = stamp
+
+		m, err := l.decode(path)
+
+		events = append(events, ProtoJsonFileEvent[M]{
+			Type:    evType,
+			Path:    path,
+			Message: m,
+			Err:     err,
+		})
+
+	}
+
+	for path := range known {
+
+		if _, ok := stamps[path]; ok {
+			continue
+		}
+
+		delete(known, path)
+
+		events = append(events, ProtoJsonFileEvent[M]{
+			Type: ProtoJson_FileDeleted,
+			Path: path,
+		})
+
+	}
+
+	return events
+
+}
+
+func (l *ProtoJsonDirLoader[M]) scan(errs map[string]error) (map[string]protoJsonFileStamp, error) {
+
+	stamps := make(map[string]protoJsonFileStamp)
+
+	err := fs.WalkDir(l.FS, l.root(), func(path string, d fs.DirEntry, err error) error {
+
+		if err != nil {
+
+			if path == l.root() {
+				return err
+			}
+
+			errs[path] = err
+
+			return nil
+
+		}
+
+		if !d.Type().IsRegular() && d.Type()&fs.ModeSymlink == 0 {
+			return nil
+		}
+
+		if _, err := ProtoJsonFileExtensionToFormat(path); err != nil {
+			return nil
+		}
+
+		info, err := d.Info()
+
+		// Symlinked files are followed, like the files of a Kubernetes
+		// ConfigMap pointing into its ..data directory
+		if err == nil && d.Type()&fs.ModeSymlink != 0 {
+			info, err = fs.Stat(l.FS, path)
+		}
+
+		if err != nil {
+			errs[path] = err
+			return nil
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		stamps[path] = protoJsonFileStamp{
+			modTime: info.ModTime(),
+			size:    info.Size(),
+		}
+
+		return nil
+
+	})
+
+	return stamps, err
+
+}
+
+func (l *ProtoJsonDirLoader[M]) decode(path string) (M, error) {
+
+	m := l.NewMessage()
+
+	enc, err := ProtoJsonFileExtensionToFormat(path)
+	if err != nil {
+		return m, err
+	}
+
+	buf, err := fs.ReadFile(l.FS, path)
+	if err != nil {
+		return m, err
+	}
+
+	if err := ProtoJsonUnmarshalWithOptions(buf, enc, m, l.Options); err != nil {
+		return m, err
+	}
+
+	return m, nil
+
+}
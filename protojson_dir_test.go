@@ -0,0 +1,151 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	app "github.com/protomesh/go-app"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/apipb"
+)
+
+func newApi() *apipb.Api {
+	return &apipb.Api{}
+}
+
+func TestProtoJsonDirLoaderLoad(t *testing.T) {
+
+	loader := &app.ProtoJsonDirLoader[*apipb.Api]{
+		FS: fstest.MapFS{
+			"routes/a.yaml":        {Data: []byte("name: a\n")},
+			"routes/nested/b.json": {Data: []byte(`{"name": "b"}`)},
+			"routes/c.toml":        {Data: []byte("name = \"c\"\n")},
+			"routes/README.md":     {Data: []byte("# Routes\n")},
+			"routes/broken.yaml":   {Data: []byte("name: broken\nunknown: true\n")},
+		},
+		Root:       "routes",
+		NewMessage: newApi,
+	}
+
+	msgs, err := loader.Load()
+
+	dirErr := &app.ProtoJsonDirError{}
+	if assert.True(t, errors.As(err, &dirErr)) {
+		assert.Len(t, dirErr.Errors, 1)
+		assert.Contains(t, dirErr.Errors, "routes/broken.yaml")
+	}
+
+	assert.Len(t, msgs, 3)
+	assert.Equal(t, "a", msgs["routes/a.yaml"].GetName())
+	assert.Equal(t, "b", msgs["routes/nested/b.json"].GetName())
+	assert.Equal(t, "c", msgs["routes/c.toml"].GetName())
+
+}
+
+func TestProtoJsonDirLoaderSymlinks(t *testing.T) {
+
+	dir := t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "..data"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "..data", "a.yaml"), []byte("name: a\n"), 0o644))
+
+	assert.NoError(t, os.Symlink(filepath.Join("..data", "a.yaml"), filepath.Join(dir, "a.yaml")))
+	assert.NoError(t, os.Symlink("..data", filepath.Join(dir, "data.yaml")))
+
+	loader := &app.ProtoJsonDirLoader[*apipb.Api]{
+		FS:         os.DirFS(dir),
+		NewMessage: newApi,
+	}
+
+	msgs, err := loader.Load()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "a", msgs["a.yaml"].GetName())
+	assert.NotContains(t, msgs, "data.yaml")
+
+}
+
+func TestProtoJsonDirLoaderWatch(t *testing.T) {
+
+	dir := t.TempDir()
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("name: a\n"), 0o644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	loader := &app.ProtoJsonDirLoader[*apipb.Api]{
+		FS:         os.DirFS(dir),
+		NewMessage: newApi,
+		Interval:   10 * time.Millisecond,
+	}
+
+	events := loader.Watch(ctx)
+
+	ev := <-events
+	assert.Equal(t, app.ProtoJson_FileAdded, ev.Type)
+	assert.Equal(t, "a.yaml", ev.Path)
+	assert.Equal(t, "a", ev.Message.GetName())
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte("name: b\n"), 0o644))
+
+	ev = <-events
+	assert.Equal(t, app.ProtoJson_FileAdded, ev.Type)
+	assert.Equal(t, "b.yaml", ev.Path)
+
+	assert.NoError(t, os.Remove(filepath.Join(dir, "a.yaml")))
+
+	ev = <-events
+	assert.Equal(t, app.ProtoJson_FileDeleted, ev.Type)
+	assert.Equal(t, "a.yaml", ev.Path)
+
+	cancel()
+
+	for range events {
+	}
+
+}
+
+func TestProtoJsonDirLoaderWatchReportsErrorsOnce(t *testing.T) {
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	loader := &app.ProtoJsonDirLoader[*apipb.Api]{
+		FS:         os.DirFS(dir),
+		Root:       "routes",
+		NewMessage: newApi,
+		Interval:   10 * time.Millisecond,
+	}
+
+	events := loader.Watch(ctx)
+
+	ev := <-events
+	assert.Equal(t, "routes", ev.Path)
+	assert.ErrorIs(t, ev.Err, os.ErrNotExist)
+
+	// Several scans fail the same way meanwhile
+	time.Sleep(50 * time.Millisecond)
+
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "routes"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "routes", "a.yaml"), []byte("name: a\n"), 0o644))
+
+	ev = <-events
+	assert.Equal(t, app.ProtoJson_FileAdded, ev.Type)
+	assert.Equal(t, "routes/a.yaml", ev.Path)
+	assert.NoError(t, ev.Err)
+
+	cancel()
+
+	for range events {
+	}
+
+}
@@ -0,0 +1,473 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var protoJsonErrorPosition = regexp.MustCompile(`\(line (\d+):\d+\)`)
+
+// ProtoJsonDecodeError is returned when a message fails to decode from a YAML
+// or TOML source, pointing to the source line of the failing field.
+type ProtoJsonDecodeError struct {
+	Format ProtoJson_SourceFormat
+	Line   int
+	Err    error
+}
+
+func (e *ProtoJsonDecodeError) Error() string {
+	return protoJsonErrorPosition.ReplaceAllString(e.Err.Error(), fmt.Sprintf("(%s line %d)", e.Format, e.Line))
+}
+
+func (e *ProtoJsonDecodeError) Unwrap() error {
+	return e.Err
+}
+
+type lineNodeKind int
+
+const (
+	lineNodeScalar lineNodeKind = iota
+	lineNodeObject
+	lineNodeArray
+)
+
+// lineNode is a JSON value annotated with the line it was declared on.
+type lineNode struct {
+	kind     lineNodeKind
+	line     int
+	raw      []byte
+	keys     []string
+	children []*lineNode
+}
+
+// lineJson is a JSON document written with one value per line, keeping the
+// source line of each JSON line to translate decoding error positions.
+type lineJson struct {
+	buf   bytes.Buffer
+	lines []int
+}
+
+func newLineJson(root *lineNode) *lineJson {
+
+	lj := &lineJson{}
+
+	lj.write(root, "", root.line)
+
+	return lj
+
+}
+
+func (lj *lineJson) writeLine(line int, text ...string) {
+
+	for _, t := range text {
+		lj.buf.WriteString(t)
+	}
+
+	lj.buf.WriteByte('\n')
+	lj.lines = append(lj.lines, line)
+
+}
+
+func (lj *lineJson) write(n *lineNode, head string, line int) {
+
+	switch n.kind {
+
+	case lineNodeScalar:
+
+		lj.writeLine(line, head, string(n.raw))
+
+	case lineNodeObject:
+
+		lj.writeLine(line, head, "{")
+
+		for i, child := range n.children {
+
+			sep := ""
+			if i > 0 {
+				sep = ","
+			}
+
+			key, _ := json.Marshal(n.keys[i])
+
+			lj.write(child, sep+string(key)+": ", child.line)
+
+		}
+
+		lj.writeLine(line, "}")
+
+	case lineNodeArray:
+
+		lj.writeLine(line, head, "[")
+
+		for i, child := range n.children {
+
+			sep := ""
+			if i > 0 {
+				sep = ","
+			}
+
+			lj.write(child, sep, child.line)
+
+		}
+
+		lj.writeLine(line, "]")
+
+	}
+
+}
+
+func (lj *lineJson) bytes() []byte {
+	return lj.buf.Bytes()
+}
+
+func (lj *lineJson) wrapError(enc ProtoJson_SourceFormat, err error) error {
+
+	// Errors without a position, like missing required fields, are returned
+	// as they are
+	match := protoJsonErrorPosition.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+
+	jsonLine, _ := strconv.Atoi(match[1])
+
+	if jsonLine < 1 || jsonLine > len(lj.lines) {
+		return err
+	}
+
+	return &ProtoJsonDecodeError{
+		Format: enc,
+		Line:   lj.lines[jsonLine-1],
+		Err:    err,
+	}
+
+}
+
+func yamlToLineNode(n *yaml.Node) (*lineNode, error) {
+
+	switch n.Kind {
+
+	case yaml.DocumentNode:
+
+		if len(n.Content) == 0 {
+			return &lineNode{kind: lineNodeObject, line: n.Line}, nil
+		}
+
+		return yamlToLineNode(n.Content[0])
+
+	case yaml.AliasNode:
+
+		return yamlToLineNode(n.Alias)
+
+	case yaml.MappingNode:
+
+		obj := &lineNode{kind: lineNodeObject, line: n.Line}
+
+		if err := appendYamlMapping(obj, n, make(map[string]bool)); err != nil {
+			return nil, err
+		}
+
+		return obj, nil
+
+	case yaml.SequenceNode:
+
+		arr := &lineNode{kind: lineNodeArray, line: n.Line}
+
+		for _, item := range n.Content {
+
+			child, err := yamlToLineNode(item)
+			if err != nil {
+				return nil, err
+			}
+
+			arr.children = append(arr.children, child)
+
+		}
+
+		return arr, nil
+
+	}
+
+	var val interface{}
+
+	if err := n.Decode(&val); err != nil {
+		return nil, err
+	}
+
+	raw, err := scalarToJson(val)
+	if err != nil {
+		return nil, fmt.Errorf("yaml: line %d: %w", n.Line, err)
+	}
+
+	return &lineNode{kind: lineNodeScalar, line: n.Line, raw: raw}, nil
+
+}
+
+func appendYamlMapping(obj *lineNode, n *yaml.Node, seen map[string]bool) error {
+
+	merges := []*yaml.Node{}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+
+		key, val := n.Content[i], n.Content[i+1]
+
+		if key.Tag == "!!merge" {
+			merges = append(merges, val)
+			continue
+		}
+
+		if seen[key.Value] {
+			continue
+		}
+
+		child, err := yamlToLineNode(val)
+		if err != nil {
+			return err
+		}
+
+		seen[key.Value] = true
+
+		obj.keys = append(obj.keys, key.Value)
+		obj.children = append(obj.children, child)
+
+	}
+
+	for _, merge := range merges {
+
+		if merge.Kind == yaml.AliasNode {
+			merge = merge.Alias
+		}
+
+		sources := []*yaml.Node{merge}
+
+		if merge.Kind == yaml.SequenceNode {
+			sources = merge.Content
+		}
+
+		for _, src := range sources {
+
+			if src.Kind == yaml.AliasNode {
+				src = src.Alias
+			}
+
+			if src.Kind != yaml.MappingNode {
+				return fmt.Errorf("yaml: line %d: merge value must be a mapping", src.Line)
+			}
+
+			if err := appendYamlMapping(obj, src, seen); err != nil {
+				return err
+			}
+
+		}
+
+	}
+
+	return nil
+
+}
+
+func scalarToJson(val interface{}) ([]byte, error) {
+
+	switch typedVal := val.(type) {
+
+	case float64:
+
+		switch {
+		case math.IsInf(typedVal, 1):
+			return []byte(`"Infinity"`), nil
+		case math.IsInf(typedVal, -1):
+			return []byte(`"-Infinity"`), nil
+		case math.IsNaN(typedVal):
+			return []byte(`"NaN"`), nil
+		}
+
+	case time.Time:
+
+		return json.Marshal(typedVal.Format(time.RFC3339Nano))
+
+	}
+
+	return json.Marshal(val)
+
+}
+
+// tomlToLineNode converts a decoded TOML document, looking up the line of each
+// value by its key path in the source text.
+func tomlToLineNode(val interface{}, path string, lines map[string]int, parentLine int) (*lineNode, error) {
+
+	line := parentLine
+	if l, ok := lines[path]; ok {
+		line = l
+	}
+
+	switch typedVal := val.(type) {
+
+	case map[string]interface{}:
+
+		obj := &lineNode{kind: lineNodeObject, line: line}
+
+		keys := make([]string, 0, len(typedVal))
+		for key := range typedVal {
+			keys = append(keys, key)
+		}
+
+		sort.Slice(keys, func(i, j int) bool {
+			return lines[joinConfigKey(path, keys[i])] < lines[joinConfigKey(path, keys[j])]
+		})
+
+		for _, key := range keys {
+
+			child, err := tomlToLineNode(typedVal[key], joinConfigKey(path, key), lines, line)
+			if err != nil {
+				return nil, err
+			}
+
+			obj.keys = append(obj.keys, key)
+			obj.children = append(obj.children, child)
+
+		}
+
+		return obj, nil
+
+	case []map[string]interface{}:
+
+		items := make([]interface{}, len(typedVal))
+		for i, item := range typedVal {
+			items[i] = item
+		}
+
+		return tomlToLineNode(items, path, lines, parentLine)
+
+	case []interface{}:
+
+		arr := &lineNode{kind: lineNodeArray, line: line}
+
+		for i, item := range typedVal {
+
+			child, err := tomlToLineNode(item, joinConfigKey(path, strconv.Itoa(i)), lines, line)
+			if err != nil {
+				return nil, err
+			}
+
+			arr.children = append(arr.children, child)
+
+		}
+
+		return arr, nil
+
+	}
+
+	raw, err := scalarToJson(val)
+	if err != nil {
+		return nil, fmt.Errorf("toml: line %d: %w", line, err)
+	}
+
+	return &lineNode{kind: lineNodeScalar, line: line, raw: raw}, nil
+
+}
+
+// scanTomlLines maps the key paths declared in a TOML document to the line
+// they're declared on. It understands table headers, arrays of tables and
+// dotted keys, which covers the positions reported in decoding errors.
+func scanTomlLines(src string) map[string]int {
+
+	lines := make(map[string]int)
+	arrayCounts := make(map[string]int)
+
+	table := ""
+	multiline := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(src))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(src)+1)
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+
+		text := strings.TrimSpace(scanner.Text())
+
+		if len(multiline) > 0 {
+
+			if strings.Contains(text, multiline) {
+				multiline = ""
+			}
+
+			continue
+
+		}
+
+		if len(text) == 0 || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[[") {
+
+			name := parseTomlKey(strings.TrimSuffix(strings.TrimPrefix(strings.SplitN(text, "]]", 2)[0], "[["), "]]"))
+
+			index := arrayCounts[name]
+			arrayCounts[name] = index + 1
+
+			if _, ok := lines[name]; !ok {
+				lines[name] = lineNum
+			}
+
+			table = joinConfigKey(name, strconv.Itoa(index))
+			lines[table] = lineNum
+
+			continue
+
+		}
+
+		if strings.HasPrefix(text, "[") {
+
+			table = parseTomlKey(strings.TrimPrefix(strings.SplitN(text, "]", 2)[0], "["))
+			lines[table] = lineNum
+
+			continue
+
+		}
+
+		sep := strings.Index(text, "=")
+		if sep < 0 {
+			continue
+		}
+
+		key := joinConfigKey(table, parseTomlKey(text[:sep]))
+
+		if _, ok := lines[key]; !ok {
+			lines[key] = lineNum
+		}
+
+		value := strings.TrimSpace(text[sep+1:])
+
+		for _, quote := range []string{`"""`, `'''`} {
+			if strings.HasPrefix(value, quote) && !strings.Contains(value[len(quote):], quote) {
+				multiline = quote
+			}
+		}
+
+	}
+
+	return lines
+
+}
+
+func parseTomlKey(raw string) string {
+
+	parts := []string{}
+
+	for _, part := range strings.Split(raw, ".") {
+		parts = append(parts, strings.Trim(strings.TrimSpace(part), `"'`))
+	}
+
+	return strings.Join(parts, ".")
+
+}
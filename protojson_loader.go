@@ -1,15 +1,25 @@
 package app
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"strings"
 
 	"github.com/BurntSushi/toml"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
 	"gopkg.in/yaml.v3"
 )
 
+var (
+	ProtoJsonEmptyInputError        = errors.New("ProtoJsonEmptyInput")
+	ProtoJsonMultipleDocumentsError = errors.New("ProtoJsonMultipleDocuments")
+)
+
 type ProtoJson_SourceFormat string
 
 const (
@@ -41,27 +51,83 @@ func ProtoJsonFileExtensionToFormat(filePath string) (ProtoJson_SourceFormat, er
 
 }
 
+type ProtoJsonUnmarshalOptions struct {
+	// DiscardUnknown ignores unknown fields instead of failing.
+	DiscardUnknown bool
+
+	// Resolver looks up the message types of google.protobuf.Any fields and
+	// extensions, protoregistry.GlobalTypes is used when nil.
+	Resolver interface {
+		protoregistry.MessageTypeResolver
+		protoregistry.ExtensionTypeResolver
+	}
+
+	// Strict rejects unknown fields (even with DiscardUnknown), empty inputs
+	// and YAML streams with more than one document when decoding a single message.
+	Strict bool
+}
+
+func (o *ProtoJsonUnmarshalOptions) protojson() protojson.UnmarshalOptions {
+
+	if o == nil {
+		return protojson.UnmarshalOptions{}
+	}
+
+	return protojson.UnmarshalOptions{
+		DiscardUnknown: o.DiscardUnknown && !o.Strict,
+		Resolver:       o.Resolver,
+	}
+
+}
+
+func (o *ProtoJsonUnmarshalOptions) strict() bool {
+	return o != nil && o.Strict
+}
+
 func ProtoJsonUnmarshal[M proto.Message](buf []byte, enc ProtoJson_SourceFormat, m M) error {
+	return ProtoJsonUnmarshalWithOptions(buf, enc, m, nil)
+}
+
+func ProtoJsonUnmarshalWithOptions[M proto.Message](buf []byte, enc ProtoJson_SourceFormat, m M, opts *ProtoJsonUnmarshalOptions) error {
+
+	if opts.strict() && len(bytes.TrimSpace(buf)) == 0 {
+		return ProtoJsonEmptyInputError
+	}
 
 	switch enc {
 
 	case ProtoJson_FromJson:
-		break
+
+		return opts.protojson().Unmarshal(buf, m)
 
 	case ProtoJson_FromYaml:
 
-		yamlMsg := make(map[string]interface{})
+		dec := yaml.NewDecoder(bytes.NewReader(buf))
 
-		err := yaml.Unmarshal([]byte(buf), &yamlMsg)
+		doc := &yaml.Node{}
+
+		err := dec.Decode(doc)
+		if err == io.EOF {
+			return opts.protojson().Unmarshal([]byte("{}"), m)
+		}
 		if err != nil {
 			return err
 		}
 
-		buf, err = json.Marshal(yamlMsg)
-		if err != nil {
-			return err
+		if opts.strict() {
+
+			err := dec.Decode(&yaml.Node{})
+			if err == nil {
+				return ProtoJsonMultipleDocumentsError
+			}
+			if err != io.EOF {
+				return err
+			}
+
 		}
 
+		return unmarshalYamlNode(doc, m, opts)
+
 	case ProtoJson_FromToml:
 
 		tomlMsg := make(map[string]interface{})
@@ -71,15 +137,129 @@ func ProtoJsonUnmarshal[M proto.Message](buf []byte, enc ProtoJson_SourceFormat,
 			return err
 		}
 
-		buf, err = json.Marshal(tomlMsg)
+		root, err := tomlToLineNode(tomlMsg, "", scanTomlLines(string(buf)), 1)
 		if err != nil {
 			return err
 		}
 
+		lj := newLineJson(root)
+
+		if err := opts.protojson().Unmarshal(lj.bytes(), m); err != nil {
+			return lj.wrapError(enc, err)
+		}
+
+		return nil
+
+	}
+
+	return UnkownConfigFormatError
+
+}
+
+// ProtoJsonUnmarshalAll decodes every document of a YAML stream (or every
+// value of a JSON stream) into a new message created by newMessage.
+func ProtoJsonUnmarshalAll[M proto.Message](buf []byte, enc ProtoJson_SourceFormat, newMessage func() M, opts *ProtoJsonUnmarshalOptions) ([]M, error) {
+
+	msgs := []M{}
+
+	switch enc {
+
+	case ProtoJson_FromJson:
+
+		dec := json.NewDecoder(bytes.NewReader(buf))
+
+		for i := 0; ; i++ {
+
+			raw := json.RawMessage{}
+
+			err := dec.Decode(&raw)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return msgs, fmt.Errorf("document %d: %w", i, err)
+			}
+
+			m := newMessage()
+
+			if err := opts.protojson().Unmarshal(raw, m); err != nil {
+				return msgs, fmt.Errorf("document %d: %w", i, err)
+			}
+
+			msgs = append(msgs, m)
+
+		}
+
+	case ProtoJson_FromYaml:
+
+		dec := yaml.NewDecoder(bytes.NewReader(buf))
+
+		for i := 0; ; i++ {
+
+			doc := &yaml.Node{}
+
+			err := dec.Decode(doc)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return msgs, fmt.Errorf("document %d: %w", i, err)
+			}
+
+			if len(doc.Content) == 0 || doc.Content[0].Tag == "!!null" {
+				continue
+			}
+
+			m := newMessage()
+
+			if err := unmarshalYamlNode(doc, m, opts); err != nil {
+				return msgs, fmt.Errorf("document %d: %w", i, err)
+			}
+
+			msgs = append(msgs, m)
+
+		}
+
+	case ProtoJson_FromToml:
+
+		m := newMessage()
+
+		if err := ProtoJsonUnmarshalWithOptions(buf, enc, m, opts); err != nil {
+			return msgs, err
+		}
+
+		msgs = append(msgs, m)
+
 	default:
-		return UnkownConfigFormatError
+		return msgs, UnkownConfigFormatError
+
+	}
 
+	if opts.strict() && len(msgs) == 0 {
+		return msgs, ProtoJsonEmptyInputError
 	}
 
-	return protojson.Unmarshal(buf, m)
+	return msgs, nil
+
+}
+
+func unmarshalYamlNode(doc *yaml.Node, m proto.Message, opts *ProtoJsonUnmarshalOptions) error {
+
+	root, err := yamlToLineNode(doc)
+	if err != nil {
+		return err
+	}
+
+	if root.kind == lineNodeScalar && string(root.raw) == "null" {
+		root = &lineNode{kind: lineNodeObject, line: root.line}
+	}
+
+	lj := newLineJson(root)
+
+	if err := opts.protojson().Unmarshal(lj.bytes(), m); err != nil {
+		return lj.wrapError(ProtoJson_FromYaml, err)
+	}
+
+	return nil
+
 }
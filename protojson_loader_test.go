@@ -0,0 +1,254 @@
+package app_test
+
+import (
+	"errors"
+	"testing"
+
+	app "github.com/protomesh/go-app"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+func TestProtoJsonUnmarshalYamlErrorLine(t *testing.T) {
+
+	buf := []byte(`name: petstore
+version: v1
+methods:
+  - name: ListPets
+    unknown: true
+`)
+
+	err := app.ProtoJsonUnmarshal(buf, app.ProtoJson_FromYaml, &apipb.Api{})
+
+	decodeErr := &app.ProtoJsonDecodeError{}
+	if assert.True(t, errors.As(err, &decodeErr)) {
+		assert.Equal(t, 5, decodeErr.Line)
+		assert.Contains(t, err.Error(), "yaml line 5")
+	}
+
+	api := &apipb.Api{}
+
+	err = app.ProtoJsonUnmarshalWithOptions(buf, app.ProtoJson_FromYaml, api, &app.ProtoJsonUnmarshalOptions{DiscardUnknown: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "ListPets", api.GetMethods()[0].GetName())
+
+}
+
+func TestProtoJsonUnmarshalTomlErrorLine(t *testing.T) {
+
+	buf := []byte(`name = "petstore"
+
+[source_context]
+file_name = "petstore.proto"
+
+[[methods]]
+name = "ListPets"
+
+[[methods]]
+name = "GetPet"
+request_streaming = "maybe"
+`)
+
+	err := app.ProtoJsonUnmarshal(buf, app.ProtoJson_FromToml, &apipb.Api{})
+
+	decodeErr := &app.ProtoJsonDecodeError{}
+	if assert.True(t, errors.As(err, &decodeErr)) {
+		assert.Equal(t, 11, decodeErr.Line)
+	}
+
+}
+
+func TestProtoJsonUnmarshalAllYamlStream(t *testing.T) {
+
+	buf := []byte(`---
+name: first
+---
+name: second
+---
+`)
+
+	apis, err := app.ProtoJsonUnmarshalAll(buf, app.ProtoJson_FromYaml, func() *apipb.Api { return &apipb.Api{} }, nil)
+	assert.NoError(t, err)
+
+	if assert.Len(t, apis, 2) {
+		assert.Equal(t, "first", apis[0].GetName())
+		assert.Equal(t, "second", apis[1].GetName())
+	}
+
+	err = app.ProtoJsonUnmarshalWithOptions(buf, app.ProtoJson_FromYaml, &apipb.Api{}, &app.ProtoJsonUnmarshalOptions{Strict: true})
+	assert.ErrorIs(t, err, app.ProtoJsonMultipleDocumentsError)
+
+}
+
+func TestProtoJsonUnmarshalDiscardUnknown(t *testing.T) {
+
+	buf := []byte(`name: petstore
+unknown: true
+`)
+
+	err := app.ProtoJsonUnmarshal(buf, app.ProtoJson_FromYaml, &apipb.Api{})
+	assert.ErrorContains(t, err, `unknown field "unknown"`)
+
+	for _, enc := range []app.ProtoJson_SourceFormat{app.ProtoJson_FromJson, app.ProtoJson_FromYaml} {
+
+		raw := buf
+		if enc == app.ProtoJson_FromJson {
+			raw = []byte(`{"name": "petstore", "unknown": true}`)
+		}
+
+		api := &apipb.Api{}
+
+		err := app.ProtoJsonUnmarshalWithOptions(raw, enc, api, &app.ProtoJsonUnmarshalOptions{DiscardUnknown: true})
+		assert.NoError(t, err, enc)
+		assert.Equal(t, "petstore", api.GetName(), enc)
+
+	}
+
+}
+
+func TestProtoJsonUnmarshalResolver(t *testing.T) {
+
+	buf := []byte(`name: petstore
+options:
+  - name: source
+    value:
+      "@type": type.googleapis.com/google.protobuf.SourceContext
+      file_name: petstore.proto
+`)
+
+	err := app.ProtoJsonUnmarshalWithOptions(buf, app.ProtoJson_FromYaml, &apipb.Api{}, &app.ProtoJsonUnmarshalOptions{
+		Resolver: &protoregistry.Types{},
+	})
+	assert.ErrorContains(t, err, "unable to resolve")
+
+	types := &protoregistry.Types{}
+	assert.NoError(t, types.RegisterMessage((&sourcecontextpb.SourceContext{}).ProtoReflect().Type()))
+
+	api := &apipb.Api{}
+
+	err = app.ProtoJsonUnmarshalWithOptions(buf, app.ProtoJson_FromYaml, api, &app.ProtoJsonUnmarshalOptions{Resolver: types})
+	if assert.NoError(t, err) && assert.Len(t, api.GetOptions(), 1) {
+
+		source := &sourcecontextpb.SourceContext{}
+
+		assert.NoError(t, api.GetOptions()[0].GetValue().UnmarshalTo(source))
+		assert.Equal(t, "petstore.proto", source.GetFileName())
+
+	}
+
+}
+
+func TestProtoJsonUnmarshalStrict(t *testing.T) {
+
+	strict := &app.ProtoJsonUnmarshalOptions{Strict: true, DiscardUnknown: true}
+
+	err := app.ProtoJsonUnmarshalWithOptions([]byte("name: petstore\nunknown: true\n"), app.ProtoJson_FromYaml, &apipb.Api{}, strict)
+	assert.ErrorContains(t, err, `unknown field "unknown"`)
+
+	for _, enc := range []app.ProtoJson_SourceFormat{app.ProtoJson_FromJson, app.ProtoJson_FromYaml, app.ProtoJson_FromToml} {
+
+		if enc != app.ProtoJson_FromJson {
+			assert.NoError(t, app.ProtoJsonUnmarshal([]byte(" \n"), enc, &apipb.Api{}), enc)
+		}
+
+		err := app.ProtoJsonUnmarshalWithOptions([]byte(" \n"), enc, &apipb.Api{}, strict)
+		assert.ErrorIs(t, err, app.ProtoJsonEmptyInputError, enc)
+
+	}
+
+	_, err = app.ProtoJsonUnmarshalAll([]byte("---\n"), app.ProtoJson_FromYaml, func() *apipb.Api { return &apipb.Api{} }, strict)
+	assert.ErrorIs(t, err, app.ProtoJsonEmptyInputError)
+
+}
+
+func TestProtoJsonUnmarshalErrorWithoutPosition(t *testing.T) {
+
+	// Missing required fields are reported without a position in the JSON
+	for _, enc := range []app.ProtoJson_SourceFormat{app.ProtoJson_FromYaml, app.ProtoJson_FromToml} {
+
+		buf := []byte("is_extension: true\n")
+		if enc == app.ProtoJson_FromToml {
+			buf = []byte("is_extension = true\n")
+		}
+
+		err := app.ProtoJsonUnmarshal(buf, enc, &descriptorpb.UninterpretedOption_NamePart{})
+		assert.ErrorContains(t, err, "required field google.protobuf.UninterpretedOption.NamePart.name_part not set", enc)
+
+		decodeErr := &app.ProtoJsonDecodeError{}
+		assert.False(t, errors.As(err, &decodeErr), enc)
+
+	}
+
+}
+
+func TestProtoJsonMarshalRoundTrip(t *testing.T) {
+
+	api := &apipb.Api{
+		Name:    "petstore.v1.PetStore",
+		Version: "v1",
+		Methods: []*apipb.Method{
+			{Name: "ListPets", ResponseStreaming: true},
+			{Name: "GetPet"},
+		},
+		SourceContext: &sourcecontextpb.SourceContext{FileName: "petstore.proto"},
+		Syntax:        typepb.Syntax_SYNTAX_PROTO3,
+	}
+
+	for _, enc := range []app.ProtoJson_SourceFormat{app.ProtoJson_FromJson, app.ProtoJson_FromYaml, app.ProtoJson_FromToml} {
+
+		raw, err := app.ProtoJsonMarshal(api, enc)
+		if !assert.NoError(t, err, enc) {
+			continue
+		}
+
+		decoded := &apipb.Api{}
+
+		assert.NoError(t, app.ProtoJsonUnmarshal(raw, enc, decoded), enc)
+		assert.True(t, proto.Equal(api, decoded), enc)
+
+	}
+
+}
+
+func TestProtoJsonMarshalFieldOrder(t *testing.T) {
+
+	raw, err := app.ProtoJsonMarshalWithOptions(&apipb.Api{Name: "petstore"}, app.ProtoJson_FromYaml, &app.ProtoJsonMarshalOptions{
+		EmitUnpopulated: true,
+		UseProtoNames:   true,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, `name: petstore
+methods: []
+options: []
+version: ""
+source_context: null
+mixins: []
+syntax: SYNTAX_PROTO2
+`, string(raw))
+
+	raw, err = app.ProtoJsonMarshal(&apipb.Api{
+		Name:          "petstore",
+		SourceContext: &sourcecontextpb.SourceContext{FileName: "petstore.proto"},
+		Methods:       []*apipb.Method{{Name: "ListPets"}},
+		Version:       "v1",
+	}, app.ProtoJson_FromToml)
+	assert.NoError(t, err)
+
+	assert.Equal(t, `name = "petstore"
+version = "v1"
+
+[[methods]]
+name = "ListPets"
+
+[sourceContext]
+fileName = "petstore.proto"
+`, string(raw))
+
+}
@@ -0,0 +1,294 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"gopkg.in/yaml.v3"
+)
+
+var tomlBareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+type ProtoJsonMarshalOptions struct {
+	// EmitUnpopulated writes the fields with default values.
+	EmitUnpopulated bool
+
+	// UseProtoNames uses the field names from the .proto file instead of the
+	// lowerCamelCase JSON names.
+	UseProtoNames bool
+
+	// Resolver looks up the message types of google.protobuf.Any fields and
+	// extensions, protoregistry.GlobalTypes is used when nil.
+	Resolver interface {
+		protoregistry.MessageTypeResolver
+		protoregistry.ExtensionTypeResolver
+	}
+}
+
+func (o *ProtoJsonMarshalOptions) protojson() protojson.MarshalOptions {
+
+	if o == nil {
+		return protojson.MarshalOptions{}
+	}
+
+	return protojson.MarshalOptions{
+		EmitUnpopulated: o.EmitUnpopulated,
+		UseProtoNames:   o.UseProtoNames,
+		Resolver:        o.Resolver,
+	}
+
+}
+
+func ProtoJsonMarshal[M proto.Message](m M, enc ProtoJson_SourceFormat) ([]byte, error) {
+	return ProtoJsonMarshalWithOptions(m, enc, nil)
+}
+
+// ProtoJsonMarshalWithOptions writes the message as JSON, YAML or TOML through
+// protojson, keeping the fields in the order they're declared in the descriptor.
+func ProtoJsonMarshalWithOptions[M proto.Message](m M, enc ProtoJson_SourceFormat, opts *ProtoJsonMarshalOptions) ([]byte, error) {
+
+	raw, err := opts.protojson().Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	switch enc {
+
+	case ProtoJson_FromJson:
+
+		buf := &bytes.Buffer{}
+
+		if err := json.Indent(buf, raw, "", "  "); err != nil {
+			return nil, err
+		}
+
+		buf.WriteByte('\n')
+
+		return buf.Bytes(), nil
+
+	case ProtoJson_FromYaml, ProtoJson_FromToml:
+
+		doc := &yaml.Node{}
+
+		if err := yaml.Unmarshal(raw, doc); err != nil {
+			return nil, err
+		}
+
+		clearYamlStyle(doc)
+
+		if enc == ProtoJson_FromToml {
+
+			if doc.Content[0].Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("toml: %s is not encoded as a table", m.ProtoReflect().Descriptor().FullName())
+			}
+
+			buf := &bytes.Buffer{}
+
+			if err := writeTomlTable(buf, nil, doc.Content[0], ""); err != nil {
+				return nil, err
+			}
+
+			return buf.Bytes(), nil
+
+		}
+
+		buf := &bytes.Buffer{}
+
+		yamlEnc := yaml.NewEncoder(buf)
+		yamlEnc.SetIndent(2)
+
+		if err := yamlEnc.Encode(doc); err != nil {
+			return nil, err
+		}
+
+		if err := yamlEnc.Close(); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+
+	}
+
+	return nil, UnkownConfigFormatError
+
+}
+
+func clearYamlStyle(n *yaml.Node) {
+
+	n.Style = 0
+
+	for _, child := range n.Content {
+		clearYamlStyle(child)
+	}
+
+}
+
+func isYamlTable(n *yaml.Node) bool {
+	return n.Kind == yaml.MappingNode && len(n.Content) > 0
+}
+
+func isYamlTableArray(n *yaml.Node) bool {
+
+	if n.Kind != yaml.SequenceNode || len(n.Content) == 0 {
+		return false
+	}
+
+	for _, item := range n.Content {
+		if item.Kind != yaml.MappingNode {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+// writeTomlTable writes the plain values of the mapping before its sub tables,
+// as TOML requires, keeping the descriptor order within each group.
+func writeTomlTable(buf *bytes.Buffer, path []string, n *yaml.Node, header string) error {
+
+	if len(header) > 0 {
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(header)
+		buf.WriteByte('\n')
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+
+		key, val := n.Content[i], n.Content[i+1]
+
+		if isYamlTable(val) || isYamlTableArray(val) || val.Tag == "!!null" {
+			continue
+		}
+
+		inline, err := tomlInlineValue(val)
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(tomlKey(key.Value))
+		buf.WriteString(" = ")
+		buf.WriteString(inline)
+		buf.WriteByte('\n')
+
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+
+		key, val := n.Content[i], n.Content[i+1]
+		subPath := append(path[:len(path):len(path)], tomlKey(key.Value))
+
+		switch {
+
+		case isYamlTable(val):
+
+			if err := writeTomlTable(buf, subPath, val, "["+strings.Join(subPath, ".")+"]"); err != nil {
+				return err
+			}
+
+		case isYamlTableArray(val):
+
+			for _, item := range val.Content {
+				if err := writeTomlTable(buf, subPath, item, "[["+strings.Join(subPath, ".")+"]]"); err != nil {
+					return err
+				}
+			}
+
+		}
+
+	}
+
+	return nil
+
+}
+
+func tomlKey(key string) string {
+
+	if tomlBareKey.MatchString(key) {
+		return key
+	}
+
+	quoted, _ := tomlString(key)
+
+	return quoted
+
+}
+
+func tomlString(val string) (string, error) {
+
+	buf := &bytes.Buffer{}
+
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(val); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+
+}
+
+func tomlInlineValue(n *yaml.Node) (string, error) {
+
+	switch n.Kind {
+
+	case yaml.MappingNode:
+
+		items := []string{}
+
+		for i := 0; i+1 < len(n.Content); i += 2 {
+
+			if n.Content[i+1].Tag == "!!null" {
+				continue
+			}
+
+			val, err := tomlInlineValue(n.Content[i+1])
+			if err != nil {
+				return "", err
+			}
+
+			items = append(items, tomlKey(n.Content[i].Value)+" = "+val)
+
+		}
+
+		if len(items) == 0 {
+			return "{}", nil
+		}
+
+		return "{ " + strings.Join(items, ", ") + " }", nil
+
+	case yaml.SequenceNode:
+
+		items := []string{}
+
+		for _, item := range n.Content {
+
+			val, err := tomlInlineValue(item)
+			if err != nil {
+				return "", err
+			}
+
+			items = append(items, val)
+
+		}
+
+		return "[" + strings.Join(items, ", ") + "]", nil
+
+	}
+
+	if n.Tag == "!!str" {
+		return tomlString(n.Value)
+	}
+
+	return n.Value, nil
+
+}
@@ -0,0 +1,355 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+type RestartPolicy string
+
+const (
+	RestartNever     RestartPolicy = "never"
+	RestartOnFailure RestartPolicy = "on-failure"
+	RestartAlways    RestartPolicy = "always"
+)
+
+type WorkerStatus string
+
+const (
+	WorkerPending    WorkerStatus = "pending"
+	WorkerRunning    WorkerStatus = "running"
+	WorkerRestarting WorkerStatus = "restarting"
+	WorkerStopped    WorkerStatus = "stopped"
+	WorkerFailed     WorkerStatus = "failed"
+)
+
+const (
+	defaultWorkerMinBackoff = time.Second
+	defaultWorkerMaxBackoff = time.Minute
+)
+
+var (
+	WorkerAlreadyRegisteredError = errors.New("WorkerAlreadyRegistered")
+	WorkerPanicError             = errors.New("WorkerPanic")
+	SupervisorStoppedError       = errors.New("SupervisorStopped")
+)
+
+type WorkerFunc func(ctx context.Context) error
+
+type WorkerOptions struct {
+	// Restart defaults to RestartOnFailure.
+	Restart RestartPolicy
+
+	// MaxRestarts gives up restarting after that many restarts, zero means
+	// no limit.
+	MaxRestarts int
+
+	// MinBackoff is the delay before the first restart, doubled after each
+	// consecutive failure up to MaxBackoff. Defaults to 1 second and 1 minute.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+type WorkerState struct {
+	Name      string
+	Status    WorkerStatus
+	Restarts  int
+	LastError error
+	StartedAt time.Time
+}
+
+type worker struct {
+	fn    WorkerFunc
+	opts  WorkerOptions
+	state WorkerState
+}
+
+// Supervisor runs named background workers, recovering their panics and
+// restarting them according to their RestartPolicy.
+type Supervisor struct {
+	log Logger
+
+	mu      sync.Mutex
+	workers map[string]*worker
+	order   []string
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+func NewSupervisor(log Logger) *Supervisor {
+	return &Supervisor{
+		log:     log,
+		workers: make(map[string]*worker),
+	}
+}
+
+// Go registers a worker, which is launched right away when the supervisor is
+// already started, otherwise on Start.
+func (s *Supervisor) Go(name string, fn WorkerFunc, opts *WorkerOptions) error {
+
+	w := &worker{
+		fn:    fn,
+		state: WorkerState{Name: name, Status: WorkerPending},
+	}
+
+	if opts != nil {
+		w.opts = *opts
+	}
+
+	if len(w.opts.Restart) == 0 {
+		w.opts.Restart = RestartOnFailure
+	}
+
+	if w.opts.MinBackoff <= 0 {
+		w.opts.MinBackoff = defaultWorkerMinBackoff
+	}
+
+	if w.opts.MaxBackoff < w.opts.MinBackoff {
+		w.opts.MaxBackoff = defaultWorkerMaxBackoff
+		if w.opts.MaxBackoff < w.opts.MinBackoff {
+			w.opts.MaxBackoff = w.opts.MinBackoff
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Stop may already be waiting for the launched workers
+	if s.stopped {
+		return fmt.Errorf("%w: can't launch %s", SupervisorStoppedError, name)
+	}
+
+	if _, ok := s.workers[name]; ok {
+		return fmt.Errorf("%w: %s", WorkerAlreadyRegisteredError, name)
+	}
+
+	s.workers[name] = w
+	s.order = append(s.order, name)
+
+	if s.ctx != nil {
+		s.launch(w)
+	}
+
+	return nil
+
+}
+
+// Start launches the registered workers, they run until Stop is called. The
+// workers get the values of the context, but not its cancellation, since it's
+// usually the one of the startup.
+func (s *Supervisor) Start(ctx context.Context) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return SupervisorStoppedError
+	}
+
+	if s.ctx != nil {
+		return nil
+	}
+
+	s.ctx, s.cancel = context.WithCancel(context.WithoutCancel(ctx))
+
+	for _, name := range s.order {
+		s.launch(s.workers[name])
+	}
+
+	return nil
+
+}
+
+// Stop cancels the workers and waits for them to return, no worker can be
+// launched afterwards.
+func (s *Supervisor) Stop(ctx context.Context) error {
+
+	s.mu.Lock()
+	s.stopped = true
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("workers still running: %w", ctx.Err())
+	}
+
+}
+
+func (s *Supervisor) State(name string) (WorkerState, bool) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.workers[name]
+	if !ok {
+		return WorkerState{}, false
+	}
+
+	return w.state, true
+
+}
+
+// States returns the state of every worker in registration order.
+func (s *Supervisor) States() []WorkerState {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states := make([]WorkerState, 0, len(s.order))
+
+	for _, name := range s.order {
+		states = append(states, s.workers[name].state)
+	}
+
+	return states
+
+}
+
+// launch must be called with the lock held, so the WaitGroup isn't added to
+// once Stop waits for it.
+func (s *Supervisor) launch(w *worker) {
+
+	s.wg.Add(1)
+
+	go func(ctx context.Context) {
+		defer s.wg.Done()
+		s.supervise(ctx, w)
+	}(s.ctx)
+
+}
+
+func (s *Supervisor) setState(w *worker, update func(state *WorkerState)) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	update(&w.state)
+
+}
+
+func (s *Supervisor) supervise(ctx context.Context, w *worker) {
+
+	failures := 0
+
+	for {
+
+		s.setState(w, func(state *WorkerState) {
+			state.Status = WorkerRunning
+			state.StartedAt = time.Now()
+		})
+
+		err := s.call(ctx, w)
+
+		if ctx.Err() != nil {
+			s.setState(w, func(state *WorkerState) {
+				state.Status = WorkerStopped
+				state.LastError = err
+			})
+			return
+		}
+
+		if err != nil {
+			failures++
+			s.log.Error("Worker failed", "worker", w.state.Name, "error", err)
+		} else {
+			failures = 0
+		}
+
+		restart := w.opts.Restart == RestartAlways || (w.opts.Restart == RestartOnFailure && err != nil)
+
+		if restart && w.opts.MaxRestarts > 0 && w.state.Restarts >= w.opts.MaxRestarts {
+			s.log.Error("Worker reached the maximum restarts", "worker", w.state.Name, "restarts", w.state.Restarts)
+			restart = false
+		}
+
+		if !restart {
+
+			s.setState(w, func(state *WorkerState) {
+				state.LastError = err
+				state.Status = WorkerStopped
+				if err != nil {
+					state.Status = WorkerFailed
+				}
+			})
+
+			return
+
+		}
+
+		s.setState(w, func(state *WorkerState) {
+			state.LastError = err
+			state.Status = WorkerRestarting
+			state.Restarts++
+		})
+
+		timer := time.NewTimer(workerBackoff(w.opts, failures))
+
+		select {
+
+		case <-timer.C:
+
+		case <-ctx.Done():
+			timer.Stop()
+			s.setState(w, func(state *WorkerState) {
+				state.Status = WorkerStopped
+			})
+			return
+
+		}
+
+	}
+
+}
+
+// call runs the worker, turning a panic into an error wrapping WorkerPanicError.
+func (s *Supervisor) call(ctx context.Context, w *worker) (err error) {
+
+	defer func() {
+
+		if r := recover(); r != nil {
+
+			err = fmt.Errorf("%w: %v", WorkerPanicError, r)
+
+			s.log.Error("Worker panicked", "worker", w.state.Name, "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+
+		}
+
+	}()
+
+	return w.fn(ctx)
+
+}
+
+func workerBackoff(opts WorkerOptions, failures int) time.Duration {
+
+	backoff := opts.MinBackoff
+
+	for i := 1; i < failures && backoff < opts.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > opts.MaxBackoff {
+		backoff = opts.MaxBackoff
+	}
+
+	return backoff
+
+}
@@ -0,0 +1,145 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	app "github.com/protomesh/go-app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type supervisedRoot struct {
+	*app.Injector[*supervisedRoot]
+
+	Workers *supervisedWorkers
+}
+
+type supervisedWorkers struct {
+	*app.Injector[*supervisedRoot]
+
+	panics   atomic.Int32
+	failures atomic.Int32
+}
+
+func (w *supervisedWorkers) Start(ctx context.Context) error {
+
+	backoff := &app.WorkerOptions{MinBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond}
+
+	err := w.Supervisor().Go("panicking", func(ctx context.Context) error {
+		if w.panics.Add(1) < 3 {
+			panic("boom")
+		}
+		<-ctx.Done()
+		return nil
+	}, backoff)
+	if err != nil {
+		return err
+	}
+
+	return w.Supervisor().Go("failing", func(ctx context.Context) error {
+		w.failures.Add(1)
+		return errors.New("failed")
+	}, &app.WorkerOptions{MaxRestarts: 2, MinBackoff: time.Millisecond})
+
+}
+
+func TestSupervisorRestartsWorkers(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{}
+
+	deps := &supervisedRoot{}
+
+	a := app.NewApp(deps, opts)
+	defer a.Close()
+
+	assert.NoError(t, a.Start(context.Background()))
+
+	assert.Eventually(t, func() bool {
+		state, _ := a.Supervisor().State("failing")
+		return state.Status == app.WorkerFailed
+	}, time.Second, time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		state, _ := a.Supervisor().State("panicking")
+		return state.Status == app.WorkerRunning && state.Restarts == 2
+	}, time.Second, time.Millisecond)
+
+	failing, ok := a.Supervisor().State("failing")
+	assert.True(t, ok)
+	assert.Equal(t, 2, failing.Restarts)
+	assert.EqualValues(t, 3, deps.Workers.failures.Load())
+
+	panicking, _ := a.Supervisor().State("panicking")
+	assert.ErrorIs(t, panicking.LastError, app.WorkerPanicError)
+
+	err := a.Supervisor().Go("failing", func(ctx context.Context) error { return nil }, nil)
+	assert.ErrorIs(t, err, app.WorkerAlreadyRegisteredError)
+
+	assert.NoError(t, a.Stop(context.Background()))
+
+	states := a.Supervisor().States()
+	assert.Equal(t, "panicking", states[0].Name)
+	assert.Equal(t, app.WorkerStopped, states[0].Status)
+
+}
+
+func TestSupervisorRestartNever(t *testing.T) {
+
+	opts, _ := newTestOptions(t)
+	opts.Args = []string{}
+
+	a := app.NewApp(&supervisedRoot{}, opts)
+	defer a.Close()
+
+	sup := a.Supervisor()
+
+	assert.NoError(t, sup.Go("once", func(ctx context.Context) error {
+		return nil
+	}, &app.WorkerOptions{Restart: app.RestartNever}))
+
+	state, _ := sup.State("once")
+	assert.Equal(t, app.WorkerPending, state.Status)
+
+	assert.NoError(t, sup.Start(context.Background()))
+
+	assert.Eventually(t, func() bool {
+		state, _ := sup.State("once")
+		return state.Status == app.WorkerStopped
+	}, time.Second, time.Millisecond)
+
+	assert.NoError(t, sup.Stop(context.Background()))
+
+}
+
+func TestSupervisorOutlivesStartContext(t *testing.T) {
+
+	supervisor := app.NewSupervisor(app.LoggerFrom(context.Background()))
+
+	running := make(chan context.Context, 1)
+
+	assert.NoError(t, supervisor.Go("worker", func(ctx context.Context) error {
+		running <- ctx
+		<-ctx.Done()
+		return nil
+	}, nil))
+
+	startCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	assert.NoError(t, supervisor.Start(startCtx))
+	cancel()
+
+	workerCtx := <-running
+	assert.NoError(t, workerCtx.Err())
+
+	assert.NoError(t, supervisor.Stop(context.Background()))
+	assert.Error(t, workerCtx.Err())
+
+	err := supervisor.Go("late", func(ctx context.Context) error { return nil }, nil)
+	assert.ErrorIs(t, err, app.SupervisorStoppedError)
+	assert.ErrorIs(t, supervisor.Start(context.Background()), app.SupervisorStoppedError)
+
+}
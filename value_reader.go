@@ -19,13 +19,21 @@ func NewConfig(val interface{}) Config {
 
 		val = typedVal.Format(time.RFC3339)
 
+	case time.Time:
+
+		val = typedVal.Format(time.RFC3339)
+
 	case time.Duration:
 
 		val = typedVal.String()
 
 	case int64:
 
-		val = strconv.FormatInt(typedVal, 64)
+		val = strconv.FormatInt(typedVal, 10)
+
+	case int:
+
+		val = strconv.Itoa(typedVal)
 
 	case float64:
 